@@ -0,0 +1,192 @@
+package logr
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+const defaultBufferSize = 1024
+
+// asyncRecord is a formatted line waiting for the background writer.
+// A record with a non-nil flushed channel is a Flush marker instead.
+type asyncRecord struct {
+	entry     LogEntry
+	formatted string
+	flushed   chan struct{}
+}
+
+// asyncWriter hands formatted lines to a background goroutine so callers
+// don't wait on the underlying writers.
+type asyncWriter struct {
+	records    chan asyncRecord
+	dropOnFull bool
+	dropped    atomic.Uint64
+
+	mu     sync.RWMutex // Guards closed against sends on a closed channel
+	closed bool
+	done   chan struct{}
+}
+
+// startAsync launches the background writer for l.
+func (l *Logger) startAsync() {
+	size := l.config.BufferSize
+	if size == 0 {
+		size = defaultBufferSize
+	}
+
+	a := &asyncWriter{
+		records:    make(chan asyncRecord, size),
+		dropOnFull: l.config.DropOnFull,
+		done:       make(chan struct{}),
+	}
+	l.async = a
+
+	go func() {
+		defer close(a.done)
+		for rec := range a.records {
+			if rec.flushed != nil {
+				close(rec.flushed)
+				continue
+			}
+			// Callers only enqueue, so a slow writer doesn't block them;
+			// writeMu is uncontended but for SetOutputs and Close.
+			l.write(rec.entry, rec.formatted)
+		}
+	}()
+}
+
+// enqueue queues a line for the background writer. When the buffer is full
+// it either blocks or, with Config.DropOnFull, discards the line and counts
+// it. Lines logged after Close are discarded.
+func (a *asyncWriter) enqueue(entry LogEntry, formatted string) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if a.closed {
+		return
+	}
+
+	rec := asyncRecord{entry: entry, formatted: formatted}
+	if !a.dropOnFull {
+		a.records <- rec
+		return
+	}
+
+	select {
+	case a.records <- rec:
+	default:
+		a.dropped.Add(1)
+	}
+}
+
+// flush blocks until every line queued before the call has been written.
+func (a *asyncWriter) flush() {
+	a.mu.RLock()
+	if a.closed {
+		a.mu.RUnlock()
+		return
+	}
+	flushed := make(chan struct{})
+	a.records <- asyncRecord{flushed: flushed}
+	a.mu.RUnlock()
+
+	<-flushed
+}
+
+// flushContext is flush bounded by ctx. On expiry it returns the number of
+// lines still queued.
+func (a *asyncWriter) flushContext(ctx context.Context) (remaining int, err error) {
+	a.mu.RLock()
+	if a.closed {
+		a.mu.RUnlock()
+		return 0, nil
+	}
+	flushed := make(chan struct{})
+	select {
+	case a.records <- asyncRecord{flushed: flushed}:
+	case <-ctx.Done():
+		a.mu.RUnlock()
+		return len(a.records), ctx.Err()
+	}
+	a.mu.RUnlock()
+
+	select {
+	case <-flushed:
+		return 0, nil
+	case <-ctx.Done():
+		// The marker itself is still queued too
+		return max(len(a.records)-1, 0), ctx.Err()
+	}
+}
+
+// close stops accepting lines and waits for the queue to drain.
+// It is safe to call more than once.
+func (a *asyncWriter) close() {
+	a.mu.Lock()
+	if !a.closed {
+		a.closed = true
+		close(a.records)
+	}
+	a.mu.Unlock()
+
+	<-a.done
+}
+
+// Flush blocks until all queued lines have been written. It is a no-op
+// for synchronous loggers.
+func (l *Logger) Flush() {
+	if l.async != nil {
+		l.async.flush()
+	}
+}
+
+// FlushContext is Flush with a bound on how long to wait, for graceful
+// shutdown when a stuck writer mustn't hold up the process:
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+//	defer cancel()
+//	if err := logger.FlushContext(ctx); err != nil {
+//		fmt.Fprintln(os.Stderr, err) // e.g. "logr: flush: 312 lines still queued: context deadline exceeded"
+//	}
+//
+// If ctx is done first it returns a *FlushError wrapping ctx.Err() with the
+// number of lines still queued; they stay queued and are written if the
+// writer recovers. Close waits for the queue without a bound, so skip it
+// after a failed flush if exiting promptly matters more than those lines.
+// It returns nil at once for synchronous loggers.
+func (l *Logger) FlushContext(ctx context.Context) error {
+	if l.async == nil {
+		return nil
+	}
+	remaining, err := l.async.flushContext(ctx)
+	if err != nil {
+		return &FlushError{Remaining: remaining, Err: err}
+	}
+	return nil
+}
+
+// FlushError is returned by FlushContext when its context is done before
+// the queue drains.
+type FlushError struct {
+	Remaining int   // Lines still queued
+	Err       error // The context's error
+}
+
+func (e *FlushError) Error() string {
+	return fmt.Sprintf("logr: flush: %d lines still queued: %v", e.Remaining, e.Err)
+}
+
+func (e *FlushError) Unwrap() error {
+	return e.Err
+}
+
+// AsyncDroppedCount returns how many lines were discarded because the async
+// buffer was full and Config.DropOnFull was set.
+func (l *Logger) AsyncDroppedCount() uint64 {
+	if l.async == nil {
+		return 0
+	}
+	return l.async.dropped.Load()
+}
@@ -0,0 +1,223 @@
+package logr
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// blockingWriter signals on its first write and then blocks every write
+// until released.
+type blockingWriter struct {
+	started chan struct{}
+	release chan struct{}
+	once    sync.Once
+
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func newBlockingWriter() *blockingWriter {
+	return &blockingWriter{
+		started: make(chan struct{}),
+		release: make(chan struct{}),
+	}
+}
+
+func (w *blockingWriter) Write(p []byte) (int, error) {
+	w.once.Do(func() { close(w.started) })
+	<-w.release
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.Write(p)
+}
+
+func (w *blockingWriter) String() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.String()
+}
+
+func asyncConfig() Config {
+	config := DefaultConfig()
+	config.Async = true
+	return config
+}
+
+func TestAsyncFlushWritesQueuedLines(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(&MockFormatter{}, LevelInfo, asyncConfig())
+	logger.SetOutput(&buf)
+	defer logger.Close()
+
+	for i := 0; i < 100; i++ {
+		logger.Info("queued")
+	}
+	logger.Flush()
+
+	if got := strings.Count(buf.String(), "queued\n"); got != 100 {
+		t.Errorf("expected 100 lines after Flush, got %d", got)
+	}
+}
+
+func TestAsyncCloseDrainsAndStops(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(&MockFormatter{}, LevelInfo, asyncConfig())
+	logger.SetOutput(&buf)
+
+	logger.Info("before close")
+	if err := logger.Close(); err != nil {
+		t.Fatalf("Close returned error: %v", err)
+	}
+	if err := logger.Close(); err != nil {
+		t.Fatalf("second Close returned error: %v", err)
+	}
+
+	logger.Info("after close")
+	logger.Flush()
+
+	if buf.String() != "before close\n" {
+		t.Errorf("expected only the line logged before Close, got %q", buf.String())
+	}
+}
+
+func TestAsyncDropOnFull(t *testing.T) {
+	config := asyncConfig()
+	config.BufferSize = 1
+	config.DropOnFull = true
+
+	w := newBlockingWriter()
+	logger := New(&MockFormatter{}, LevelInfo, config)
+	logger.SetOutput(w)
+
+	logger.Info("first") // picked up by the writer, which then blocks
+	<-w.started
+	logger.Info("second") // fills the buffer
+	logger.Info("third")  // dropped
+	logger.Info("fourth") // dropped
+
+	if got := logger.AsyncDroppedCount(); got != 2 {
+		t.Errorf("expected 2 dropped lines, got %d", got)
+	}
+
+	close(w.release)
+	logger.Close()
+
+	if w.String() != "first\nsecond\n" {
+		t.Errorf("expected first and second to be written, got %q", w.String())
+	}
+}
+
+func TestAsyncConcurrentLogging(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(&PlainTextFormatter{}, LevelInfo, asyncConfig())
+	logger.SetOutput(&buf)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			logger.Info("concurrent")
+		}()
+	}
+	wg.Wait()
+	logger.Close()
+
+	if got := strings.Count(buf.String(), "] concurrent\n"); got != 50 {
+		t.Errorf("expected 50 lines, got %d", got)
+	}
+}
+
+func TestSyncFlushAndCloseAreNoOps(t *testing.T) {
+	logger := New(&MockFormatter{}, LevelInfo, DefaultConfig())
+	logger.Flush()
+	if err := logger.Close(); err != nil {
+		t.Errorf("expected nil error, got %v", err)
+	}
+	if logger.AsyncDroppedCount() != 0 {
+		t.Errorf("expected no drops for sync logger")
+	}
+}
+
+func TestAsyncFlushContextTimesOutOnStuckWriter(t *testing.T) {
+	w := newBlockingWriter()
+	logger := New(&MockFormatter{}, LevelInfo, asyncConfig())
+	logger.SetOutput(w)
+
+	logger.Info("stuck") // picked up by the writer, which then blocks
+	<-w.started
+	logger.Info("queued one")
+	logger.Info("queued two")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err := logger.FlushContext(ctx)
+
+	var flushErr *FlushError
+	if !errors.As(err, &flushErr) {
+		t.Fatalf("FlushContext() error = %v, want a *FlushError", err)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("FlushContext() error = %v, want it to wrap context.DeadlineExceeded", err)
+	}
+	if flushErr.Remaining != 2 {
+		t.Errorf("Remaining = %d, want 2", flushErr.Remaining)
+	}
+
+	// Queued lines are still written once the writer recovers
+	close(w.release)
+	logger.Close()
+	if w.String() != "stuck\nqueued one\nqueued two\n" {
+		t.Errorf("expected every line after recovery, got %q", w.String())
+	}
+}
+
+func TestAsyncFlushContextOnFullQueue(t *testing.T) {
+	config := asyncConfig()
+	config.BufferSize = 1
+
+	w := newBlockingWriter()
+	logger := New(&MockFormatter{}, LevelInfo, config)
+	logger.SetOutput(w)
+
+	logger.Info("stuck")
+	<-w.started
+	logger.Info("fills the buffer")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	var flushErr *FlushError
+	if err := logger.FlushContext(ctx); !errors.As(err, &flushErr) || flushErr.Remaining != 1 {
+		t.Errorf("FlushContext() error = %v, want a *FlushError with 1 line remaining", err)
+	}
+
+	close(w.release)
+	logger.Close()
+}
+
+func TestAsyncFlushContextDrains(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(&MockFormatter{}, LevelInfo, asyncConfig())
+	logger.SetOutput(&buf)
+	defer logger.Close()
+
+	for i := 0; i < 100; i++ {
+		logger.Info("queued")
+	}
+	if err := logger.FlushContext(context.Background()); err != nil {
+		t.Fatalf("FlushContext() error = %v", err)
+	}
+	if got := strings.Count(buf.String(), "queued\n"); got != 100 {
+		t.Errorf("expected 100 lines after FlushContext, got %d", got)
+	}
+
+	if err := New(&MockFormatter{}, LevelInfo, DefaultConfig()).FlushContext(context.Background()); err != nil {
+		t.Errorf("FlushContext() on a sync logger error = %v, want nil", err)
+	}
+}
@@ -0,0 +1,150 @@
+package logr
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	defaultMaxBatch      = 100
+	defaultFlushInterval = time.Second
+)
+
+// BatchSink is an io.Writer that collects lines and passes them to Writer
+// in a single Write once MaxBatch lines have accumulated or FlushInterval
+// has passed since the first line of the batch, whichever comes first.
+// Wrapping a NetworkSink this way turns one round-trip per line into one
+// per batch:
+//
+//	net := &logr.NetworkSink{Network: "tcp", Address: "collector:5170"}
+//	batch := &logr.BatchSink{Writer: net, MaxBatch: 500, FlushInterval: 2 * time.Second}
+//	defer net.Close()
+//	defer batch.Close()
+//	logger.AddSink(batch)
+//
+// A batch that fills up is written on the logging goroutine, which sees
+// any error. Batches flushed by the timer report errors to ErrorHandler.
+// A batch whose write fails is discarded. Because a batch mixes levels,
+// Writer receives plain Write calls even if it is a LevelWriter.
+//
+// Close flushes the partial batch but leaves Writer open. BatchSink is
+// safe for concurrent use.
+type BatchSink struct {
+	Writer        io.Writer
+	MaxBatch      int           // Zero means 100
+	FlushInterval time.Duration // Zero means 1s
+	ErrorHandler  ErrorHandler  // Nil means print to os.Stderr
+
+	mu     sync.Mutex
+	buf    []byte
+	lines  int
+	timer  *time.Timer // Running while the batch holds lines
+	gen    uint64      // Bumped on every flush so a stale timer does nothing
+	closed bool
+}
+
+func (s *BatchSink) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return 0, fmt.Errorf("logr: write to batch sink: %w", os.ErrClosed)
+	}
+
+	s.buf = append(s.buf, p...)
+	s.lines++
+
+	if s.lines >= s.maxBatch() {
+		if err := s.flushLocked(); err != nil {
+			return 0, err
+		}
+		return len(p), nil
+	}
+
+	if s.timer == nil {
+		gen := s.gen
+		s.timer = time.AfterFunc(s.flushInterval(), func() { s.flushOnTimer(gen) })
+	}
+	return len(p), nil
+}
+
+// Flush writes the current batch now.
+func (s *BatchSink) Flush() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.flushLocked()
+}
+
+// Close flushes the current batch and stops the timer. Later writes fail.
+func (s *BatchSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	return s.flushLocked()
+}
+
+func (s *BatchSink) maxBatch() int {
+	if s.MaxBatch <= 0 {
+		return defaultMaxBatch
+	}
+	return s.MaxBatch
+}
+
+func (s *BatchSink) flushInterval() time.Duration {
+	if s.FlushInterval <= 0 {
+		return defaultFlushInterval
+	}
+	return s.FlushInterval
+}
+
+// flushOnTimer flushes the batch that was current when the timer for gen
+// was started, unless it has been flushed already.
+func (s *BatchSink) flushOnTimer(gen uint64) {
+	s.mu.Lock()
+	if gen != s.gen {
+		s.mu.Unlock()
+		return
+	}
+	err := s.flushLocked()
+	s.mu.Unlock()
+
+	if err != nil {
+		s.handleError(err)
+	}
+}
+
+// flushLocked writes the batch, if any, and starts a new one. Callers must
+// hold s.mu.
+func (s *BatchSink) flushLocked() error {
+	if s.timer != nil {
+		s.timer.Stop()
+		s.timer = nil
+	}
+	s.gen++
+
+	if s.lines == 0 {
+		return nil
+	}
+	_, err := s.Writer.Write(s.buf)
+	s.buf = s.buf[:0]
+	s.lines = 0
+	if err != nil {
+		return fmt.Errorf("logr: flush batch: %w", err)
+	}
+	return nil
+}
+
+func (s *BatchSink) handleError(err error) {
+	if s.ErrorHandler != nil {
+		s.ErrorHandler(err)
+		return
+	}
+	stderrErrorHandler(err)
+}
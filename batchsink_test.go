@@ -0,0 +1,139 @@
+package logr
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// writeRecorder records each Write call separately.
+type writeRecorder struct {
+	mu     sync.Mutex
+	writes []string
+	err    error
+}
+
+func (w *writeRecorder) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.err != nil {
+		return 0, w.err
+	}
+	w.writes = append(w.writes, string(p))
+	return len(p), nil
+}
+
+func (w *writeRecorder) Writes() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return append([]string(nil), w.writes...)
+}
+
+func TestBatchSinkFlushesFullBatch(t *testing.T) {
+	rec := &writeRecorder{}
+	sink := &BatchSink{Writer: rec, MaxBatch: 3, FlushInterval: time.Hour}
+	defer sink.Close()
+
+	for _, line := range []string{"a\n", "b\n", "c\n", "d\n"} {
+		if _, err := sink.Write([]byte(line)); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+
+	writes := rec.Writes()
+	if len(writes) != 1 || writes[0] != "a\nb\nc\n" {
+		t.Errorf("writes = %q, want one batch %q", writes, "a\nb\nc\n")
+	}
+}
+
+func TestBatchSinkFlushesOnInterval(t *testing.T) {
+	rec := &writeRecorder{}
+	sink := &BatchSink{Writer: rec, MaxBatch: 100, FlushInterval: 10 * time.Millisecond}
+	defer sink.Close()
+
+	sink.Write([]byte("a\n"))
+	sink.Write([]byte("b\n"))
+
+	deadline := time.Now().Add(2 * time.Second)
+	for len(rec.Writes()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	writes := rec.Writes()
+	if len(writes) != 1 || writes[0] != "a\nb\n" {
+		t.Errorf("writes = %q, want one batch %q", writes, "a\nb\n")
+	}
+}
+
+func TestBatchSinkCloseFlushesPartialBatch(t *testing.T) {
+	rec := &writeRecorder{}
+	sink := &BatchSink{Writer: rec, MaxBatch: 100, FlushInterval: time.Hour}
+
+	sink.Write([]byte("partial\n"))
+	if err := sink.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	if writes := rec.Writes(); len(writes) != 1 || writes[0] != "partial\n" {
+		t.Errorf("writes = %q, want the partial batch", writes)
+	}
+	if _, err := sink.Write([]byte("late\n")); !errors.Is(err, os.ErrClosed) {
+		t.Errorf("Write() after Close error = %v, want os.ErrClosed", err)
+	}
+}
+
+func TestBatchSinkReportsTimerFlushErrors(t *testing.T) {
+	reported := make(chan error, 1)
+	rec := &writeRecorder{err: errors.New("collector down")}
+	sink := &BatchSink{
+		Writer:        rec,
+		FlushInterval: 5 * time.Millisecond,
+		ErrorHandler:  func(err error) { reported <- err },
+	}
+	defer sink.Close()
+
+	sink.Write([]byte("lost\n"))
+
+	select {
+	case err := <-reported:
+		if !strings.Contains(err.Error(), "collector down") {
+			t.Errorf("reported error = %v, want the write error", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected the timer flush error to be reported")
+	}
+}
+
+func TestBatchSinkAsLoggerSink(t *testing.T) {
+	rec := &writeRecorder{}
+	sink := &BatchSink{Writer: rec, MaxBatch: 50, FlushInterval: time.Hour}
+
+	logger := New(&PlainTextFormatter{}, LevelInfo, DefaultConfig())
+	logger.SetOutput(&bytes.Buffer{})
+	logger.AddSink(sink)
+
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 30; i++ {
+				logger.Info("batched")
+			}
+		}()
+	}
+	wg.Wait()
+	sink.Close()
+
+	writes := rec.Writes()
+	if len(writes) != 3 {
+		t.Errorf("got %d writes, want 3 (50 + 50 + 20 lines)", len(writes))
+	}
+	if lines := strings.Count(strings.Join(writes, ""), "batched\n"); lines != 120 {
+		t.Errorf("got %d lines, want 120", lines)
+	}
+}
@@ -2,6 +2,7 @@ package logr
 
 import (
 	"fmt"
+	"io"
 	"testing"
 )
 
@@ -120,7 +121,7 @@ func BenchmarkResolveLayerUncached(b *testing.B) {
 	for i := 0; i < b.N; i++ {
 		// Clear cache before each iteration to simulate cold start
 		logger.registryMu.Lock()
-		delete(logger.layerCache, packagePath)
+		logger.layerCache.remove(packagePath)
 		logger.registryMu.Unlock()
 
 		_ = resolveLayer(logger, packagePath)
@@ -151,7 +152,7 @@ func BenchmarkResolveLayerWithInheritance(b *testing.B) {
 	for i := 0; i < b.N; i++ {
 		// Clear cache to force inheritance lookup
 		logger.registryMu.Lock()
-		delete(logger.layerCache, childPkg)
+		logger.layerCache.remove(childPkg)
 		logger.registryMu.Unlock()
 
 		_ = resolveLayer(logger, childPkg)
@@ -195,6 +196,65 @@ func BenchmarkLoggerInfoWithMetadata(b *testing.B) {
 	}
 }
 
+// BenchmarkLoggerInfow measures logging with key-value pairs, whose
+// metadata the logger builds and recycles itself
+func BenchmarkLoggerInfow(b *testing.B) {
+	logger := New(&PlainTextFormatter{}, LevelInfo, Config{DefaultDepth: 2})
+	logger.SetOutput(io.Discard)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		logger.Infow("request processed", "requestID", "abc123", "userID", 456)
+	}
+}
+
+// BenchmarkLoggerParallel measures logging from many goroutines at once.
+// JSON formatting runs in parallel; only the write is serialized.
+func BenchmarkLoggerParallel(b *testing.B) {
+	logger := New(JSONFormatter{}, LevelInfo, Config{DefaultDepth: 2})
+	logger.SetOutput(io.Discard)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			logger.Infow("request processed", "requestID", "abc123", "userID", 456, "path", "/api/users")
+		}
+	})
+}
+
+// BenchmarkEntryInfo measures logging through an Entry with bound fields
+func BenchmarkEntryInfo(b *testing.B) {
+	logger := New(&PlainTextFormatter{}, LevelInfo, Config{DefaultDepth: 2})
+	logger.SetOutput(io.Discard)
+	entry := logger.WithField("requestID", "abc123").WithField("userID", 456)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		entry.Info("request processed")
+	}
+}
+
+// BenchmarkLoggerEnabled measures the guard for a filtered-out level
+func BenchmarkLoggerEnabled(b *testing.B) {
+	logger := New(&PlainTextFormatter{}, LevelInfo, Config{DefaultDepth: 2})
+	logger.SetOutput(io.Discard)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if logger.Enabled(LevelDebug) {
+			logger.Debugf("state: %v", i)
+		}
+	}
+}
+
 // BenchmarkPlainTextFormatter measures formatting performance
 func BenchmarkPlainTextFormatter(b *testing.B) {
 	formatter := &PlainTextFormatter{}
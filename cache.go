@@ -0,0 +1,125 @@
+package logr
+
+import (
+	"container/list"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// layerCache maps package paths to resolved layer names. With a non-zero
+// max it evicts the least recently used entry once full. Logger guards it
+// with registryMu: get and peek only need the read lock, everything else
+// needs the write lock.
+type layerCache struct {
+	max     int // Zero means unbounded
+	entries map[string]*list.Element
+	lru     *list.List // Front is the most recently used
+
+	lruMu sync.Mutex // Serializes recency updates from concurrent get calls
+
+	hits   atomic.Uint64 // Lookups through Logger.getCachedLayer
+	misses atomic.Uint64
+}
+
+type layerCacheEntry struct {
+	pkgPath string
+	layer   string
+}
+
+func newLayerCache(max int) *layerCache {
+	return &layerCache{
+		max:     max,
+		entries: make(map[string]*list.Element),
+		lru:     list.New(),
+	}
+}
+
+// get returns the cached layer for pkgPath and marks it as recently used.
+// Recency only matters for a bounded cache, so an unbounded one skips it.
+func (c *layerCache) get(pkgPath string) (string, bool) {
+	elem, ok := c.entries[pkgPath]
+	if !ok {
+		return "", false
+	}
+	if c.max > 0 {
+		c.lruMu.Lock()
+		c.lru.MoveToFront(elem)
+		c.lruMu.Unlock()
+	}
+	return elem.Value.(*layerCacheEntry).layer, true
+}
+
+// peek is like get but leaves the recency order untouched.
+func (c *layerCache) peek(pkgPath string) (string, bool) {
+	elem, ok := c.entries[pkgPath]
+	if !ok {
+		return "", false
+	}
+	return elem.Value.(*layerCacheEntry).layer, true
+}
+
+// set stores layer for pkgPath, evicting the least recently used entry if
+// the cache is over its limit.
+func (c *layerCache) set(pkgPath string, layer string) {
+	if elem, ok := c.entries[pkgPath]; ok {
+		elem.Value.(*layerCacheEntry).layer = layer
+		c.lru.MoveToFront(elem)
+		return
+	}
+
+	c.entries[pkgPath] = c.lru.PushFront(&layerCacheEntry{pkgPath: pkgPath, layer: layer})
+
+	if c.max > 0 && c.lru.Len() > c.max {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.entries, oldest.Value.(*layerCacheEntry).pkgPath)
+	}
+}
+
+func (c *layerCache) remove(pkgPath string) {
+	if elem, ok := c.entries[pkgPath]; ok {
+		c.lru.Remove(elem)
+		delete(c.entries, pkgPath)
+	}
+}
+
+// removeTree removes pkgPath and every package path nested under it,
+// e.g. "myapp/db" removes "myapp/db/postgres" but not "myapp/dbutil".
+func (c *layerCache) removeTree(pkgPath string) {
+	prefix := pkgPath + "/"
+	for key, elem := range c.entries {
+		if key == pkgPath || strings.HasPrefix(key, prefix) {
+			c.lru.Remove(elem)
+			delete(c.entries, key)
+		}
+	}
+}
+
+func (c *layerCache) len() int {
+	return c.lru.Len()
+}
+
+func (c *layerCache) clear() {
+	clear(c.entries)
+	c.lru.Init()
+}
+
+// ClearCache discards every resolved layer so the next log call from each
+// package resolves it again. Hit and miss counts are kept.
+func (l *Logger) ClearCache() {
+	l.registryMu.Lock()
+	defer l.registryMu.Unlock()
+
+	l.layerCache.clear()
+}
+
+// CacheStats reports how many package paths are cached and how many layer
+// lookups were served from the cache or had to be resolved.
+func (l *Logger) CacheStats() (size int, hits, misses uint64) {
+	l.registryMu.RLock()
+	size = l.layerCache.len()
+	l.registryMu.RUnlock()
+
+	return size, l.layerCache.hits.Load(), l.layerCache.misses.Load()
+}
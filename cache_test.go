@@ -0,0 +1,159 @@
+package logr
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestLayerCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	c := newLayerCache(2)
+
+	c.set("myapp/a", "A")
+	c.set("myapp/b", "B")
+
+	// Touch a so b becomes the least recently used
+	if _, ok := c.get("myapp/a"); !ok {
+		t.Fatal("expected myapp/a to be cached")
+	}
+
+	c.set("myapp/c", "C")
+
+	if _, ok := c.peek("myapp/b"); ok {
+		t.Error("expected myapp/b to be evicted")
+	}
+	for _, pkg := range []string{"myapp/a", "myapp/c"} {
+		if _, ok := c.peek(pkg); !ok {
+			t.Errorf("expected %s to still be cached", pkg)
+		}
+	}
+	if c.len() != 2 {
+		t.Errorf("len() = %d, want 2", c.len())
+	}
+}
+
+func TestLayerCacheUpdateDoesNotGrow(t *testing.T) {
+	c := newLayerCache(2)
+
+	c.set("myapp/a", "A")
+	c.set("myapp/a", "A2")
+
+	if got, _ := c.peek("myapp/a"); got != "A2" {
+		t.Errorf("peek() = %q, want %q", got, "A2")
+	}
+	if c.len() != 1 {
+		t.Errorf("len() = %d, want 1", c.len())
+	}
+}
+
+func TestLayerCacheUnbounded(t *testing.T) {
+	c := newLayerCache(0)
+
+	for i := 0; i < 100; i++ {
+		c.set(fmt.Sprintf("myapp/pkg%d", i), "PKG")
+	}
+
+	if c.len() != 100 {
+		t.Errorf("len() = %d, want 100", c.len())
+	}
+}
+
+func TestLoggerMaxCacheSize(t *testing.T) {
+	config := DefaultConfig()
+	config.MaxCacheSize = 1
+	logger := New(&PlainTextFormatter{}, LevelInfo, config)
+
+	resolveLayer(logger, "myapp/api/handlers")
+	resolveLayer(logger, "myapp/db/postgres")
+
+	if _, ok := logger.getCachedLayer("myapp/api/handlers"); ok {
+		t.Error("expected the first package to be evicted")
+	}
+	if got, ok := logger.getCachedLayer("myapp/db/postgres"); !ok || got != "DB/POSTGRES" {
+		t.Errorf("getCachedLayer() = %q, %v, want %q, true", got, ok, "DB/POSTGRES")
+	}
+}
+
+func TestClearCache(t *testing.T) {
+	logger := New(&PlainTextFormatter{}, LevelInfo, DefaultConfig())
+
+	resolveLayer(logger, "myapp/api/handlers")
+	resolveLayer(logger, "myapp/db/postgres")
+
+	logger.ClearCache()
+
+	if size, _, _ := logger.CacheStats(); size != 0 {
+		t.Errorf("size after ClearCache = %d, want 0", size)
+	}
+	if _, ok := logger.getCachedLayer("myapp/api/handlers"); ok {
+		t.Error("expected cache to be empty after ClearCache")
+	}
+}
+
+func TestCacheStats(t *testing.T) {
+	logger := New(&PlainTextFormatter{}, LevelInfo, DefaultConfig())
+
+	resolveLayer(logger, "myapp/api/handlers") // miss
+	resolveLayer(logger, "myapp/api/handlers") // hit
+	resolveLayer(logger, "myapp/api/handlers") // hit
+	resolveLayer(logger, "myapp/db/postgres")  // miss
+
+	size, hits, misses := logger.CacheStats()
+	if size != 2 || hits != 2 || misses != 2 {
+		t.Errorf("CacheStats() = %d, %d, %d, want 2, 2, 2", size, hits, misses)
+	}
+}
+
+func TestSetLayerInvalidatesCachedChildren(t *testing.T) {
+	logger := New(&PlainTextFormatter{}, LevelInfo, DefaultConfig())
+
+	// Children resolve (and cache) their depth-derived layers first
+	for pkg, want := range map[string]string{
+		"myapp/db/postgres": "DB/POSTGRES",
+		"myapp/db/redis":    "DB/REDIS",
+		"myapp/dbutil":      "MYAPP/DBUTIL",
+	} {
+		if got := resolveLayer(logger, pkg); got != want {
+			t.Fatalf("resolveLayer(%q) = %q, want %q", pkg, got, want)
+		}
+	}
+
+	if err := logger.setLayerForPath("myapp/db", "Database"); err != nil {
+		t.Fatalf("setLayerForPath() error = %v", err)
+	}
+
+	for _, pkg := range []string{"myapp/db/postgres", "myapp/db/redis"} {
+		if got := resolveLayer(logger, pkg); got != "Database" {
+			t.Errorf("resolveLayer(%q) = %q, want %q", pkg, got, "Database")
+		}
+	}
+
+	// A sibling that only shares a string prefix keeps its cached layer
+	if _, ok := logger.getCachedLayer("myapp/dbutil"); !ok {
+		t.Error("expected myapp/dbutil to stay cached")
+	}
+}
+
+func TestConcurrentBoundedCacheLookups(t *testing.T) {
+	config := DefaultConfig()
+	config.MaxCacheSize = 2
+	logger := New(&PlainTextFormatter{}, LevelInfo, config)
+
+	packagePaths := []string{"myapp/api/handlers", "myapp/db/postgres", "myapp/cache/redis"}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(idx int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				resolveLayer(logger, packagePaths[(idx+j)%len(packagePaths)])
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if size, _, _ := logger.CacheStats(); size > 2 {
+		t.Errorf("cache size = %d, want at most 2", size)
+	}
+}
@@ -0,0 +1,66 @@
+package logr
+
+import (
+	"io"
+	"os"
+)
+
+const colorReset = "\x1b[0m"
+
+var levelColors = map[Level]string{
+	LevelTrace: "\x1b[2m",  // dim
+	LevelDebug: "\x1b[90m", // gray
+	LevelInfo:  "\x1b[32m", // green
+	LevelWarn:  "\x1b[33m", // yellow
+	LevelError: "\x1b[31m", // red
+	LevelTest:  "\x1b[36m", // cyan
+}
+
+// ColorFormatter renders lines exactly like PlainTextFormatter, with the
+// level tag wrapped in ANSI colors. Create it with NewColorFormatter so
+// colors are only used when the destination is a terminal; the zero value
+// prints without colors.
+type ColorFormatter struct {
+	PlainTextFormatter
+	enabled bool
+}
+
+// NewColorFormatter returns a ColorFormatter for output written to w.
+// Colors are disabled when the NO_COLOR environment variable is set or
+// when w is not a terminal.
+func NewColorFormatter(w io.Writer) *ColorFormatter {
+	return &ColorFormatter{enabled: colorEnabled(w)}
+}
+
+func (f *ColorFormatter) Format(entry LogEntry) string {
+	line := f.PlainTextFormatter.Format(entry)
+	color, ok := levelColors[entry.Level]
+	if !f.enabled || !ok {
+		return line
+	}
+
+	tag := "[" + entry.Level.String() + "]"
+	return color + tag + colorReset + line[len(tag):]
+}
+
+// colorEnabled reports whether ANSI colors should be written to w.
+// See https://no-color.org for the NO_COLOR convention.
+func colorEnabled(w io.Writer) bool {
+	if _, ok := os.LookupEnv("NO_COLOR"); ok {
+		return false
+	}
+	return isTerminal(w)
+}
+
+// isTerminal reports whether w is a character device such as a TTY.
+func isTerminal(w io.Writer) bool {
+	file, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := file.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,39 @@
+package logr
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestColorFormatterWrapsLevelTag(t *testing.T) {
+	entry := LogEntry{
+		Level:     LevelWarn,
+		Layer:     LayerHTTP,
+		Message:   "slow request",
+		Timestamp: time.Date(2025, 9, 29, 12, 0, 0, 0, time.UTC),
+	}
+
+	plain := (&PlainTextFormatter{}).Format(entry)
+	colored := (&ColorFormatter{enabled: true}).Format(entry)
+
+	want := "\x1b[33m[WARN]\x1b[0m" + strings.TrimPrefix(plain, "[WARN]")
+	if colored != want {
+		t.Errorf("expected %q, got %q", want, colored)
+	}
+}
+
+func TestColorFormatterZeroValueIsPlain(t *testing.T) {
+	entry := NewEntry(LevelError, LayerDB, "query failed")
+
+	if got, want := (&ColorFormatter{}).Format(*entry), (&PlainTextFormatter{}).Format(*entry); got != want {
+		t.Errorf("expected plain output %q, got %q", want, got)
+	}
+}
+
+func TestNewColorFormatterDisabledForNonTerminal(t *testing.T) {
+	if NewColorFormatter(&bytes.Buffer{}).enabled {
+		t.Error("expected colors disabled for a bytes.Buffer")
+	}
+}
@@ -2,7 +2,10 @@ package logr
 
 import (
 	"fmt"
+	"regexp"
+	"runtime/debug"
 	"slices"
+	"strings"
 )
 
 const (
@@ -10,6 +13,14 @@ const (
 	strictMode   = false
 )
 
+// ModulePrefixAuto is the Config.ModulePrefix value that strips the main
+// module path reported by runtime/debug.ReadBuildInfo, the same as setting
+// AutoModulePrefix.
+const ModulePrefixAuto = "auto"
+
+// readBuildInfo is swapped out in tests to simulate missing build info.
+var readBuildInfo = debug.ReadBuildInfo
+
 // Config holds global logger configuration options.
 type Config struct {
 	// DefaultDepth specifies the starting index in the package path
@@ -21,13 +32,138 @@ type Config struct {
 	// displaying layers. Common examples: "internal", "pkg", "adapters".
 	SkipSegments []string
 
+	// SkipPatterns lists regular expressions; segments matching any of
+	// them are filtered out like SkipSegments, e.g. `^v[0-9]+$` for
+	// version segments. Patterns are compiled by Validate, which
+	// InitWithConfig and New call.
+	SkipPatterns []string
+
+	// ModulePrefix is stripped from package paths before DefaultDepth is
+	// applied, so depth counts from the project root: with ModulePrefix
+	// "github.com/myorg/myservice", "github.com/myorg/myservice/api/handlers"
+	// is treated as "api/handlers". Use ModulePrefixAuto to strip the main
+	// module path from the build info. Empty strips nothing unless
+	// AutoModulePrefix is set.
+	ModulePrefix string
+
+	// AutoModulePrefix strips the main module path reported by the build
+	// info, so "github.com/myorg/app/api/handlers" in module
+	// "github.com/myorg/app" resolves from "api/handlers". A non-empty
+	// ModulePrefix takes precedence, and is also the way to set the prefix
+	// when build info is unavailable. DefaultConfig turns this on.
+	AutoModulePrefix bool
+
+	// DefaultLayer is the layer used when none can be resolved from the
+	// package path: every segment is skipped, the path is empty or the
+	// depth is not positive. Empty means LayerUnknown.
+	DefaultLayer Layer
+
 	// StrictMode, when enabled, only allows layers specified in AllowedLayers.
-	// Attempting to use an unlisted layer will cause a panic.
+	// Attempting to use an unlisted layer is handled per StrictModeAction,
+	// which panics by default.
 	StrictMode bool
 
+	// StrictModeAction chooses between failing hard and degrading softly
+	// when StrictMode rejects a layer. Ignored when StrictMode is false.
+	StrictModeAction StrictModeAction
+
 	// AllowedLayers defines the valid layers when StrictMode is enabled.
 	// Ignored when StrictMode is false.
 	AllowedLayers []Layer
+
+	// LayerCase sets the casing of layers derived from the package path.
+	// The zero value, LayerCaseUpper, upper-cases them.
+	LayerCase LayerCase
+
+	// LayerSeparator joins the segments of a derived layer, e.g. "." for
+	// DB.POSTGRES. DefaultConfig sets "/"; empty joins them with nothing
+	// in between, e.g. DBPOSTGRES.
+	LayerSeparator string
+
+	// IncludeCaller records the file and line of the logging call site
+	// on each entry (e.g. "main.go:42").
+	IncludeCaller bool
+
+	// CaptureStackOnError records the stack trace of the logging call
+	// on entries at LevelError and above.
+	CaptureStackOnError bool
+
+	// TimeFormat is the time.Format layout used for timestamps, or one of
+	// TimeFormatUnix / TimeFormatUnixMs for epoch seconds / milliseconds.
+	// Empty means RFC3339.
+	TimeFormat string
+
+	// EscapeNewlines makes PlainTextFormatter and ColorFormatter write CR
+	// and LF in messages and metadata values as \r and \n, so every entry
+	// stays on one physical line for parsers that split on newlines.
+	// Stack traces from CaptureStackOnError still follow on indented lines.
+	EscapeNewlines bool
+
+	// UTC stores entry timestamps in UTC instead of local time, so logs
+	// from machines in different time zones line up.
+	UTC bool
+
+	// Async hands formatted lines to a background goroutine instead of
+	// writing them on the calling goroutine. Call Logger.Flush or
+	// Logger.Close before exiting so queued lines are not lost.
+	Async bool
+
+	// BufferSize is the number of lines the async queue holds.
+	// Zero means 1024. Ignored unless Async is set.
+	BufferSize int
+
+	// DropOnFull makes logging calls discard lines when the async queue is
+	// full instead of blocking until there is room. Discarded lines are
+	// counted by Logger.AsyncDroppedCount.
+	DropOnFull bool
+
+	// DedupConsecutive collapses runs of consecutive entries with the same
+	// level, layer, message, metadata and error: the first is written, the
+	// repeats are dropped, and once a different entry arrives, or on Close,
+	// a line such as "last message repeated 57 times" is written in their
+	// place. Each output and sink keeps its own runs, over the entries that
+	// reach it. Hooks, processors and Metrics still see every repeat.
+	DedupConsecutive bool
+
+	// RedactKeys lists metadata keys whose values are replaced with
+	// RedactedValue before formatting. Matching is case-insensitive.
+	RedactKeys []string
+
+	// RedactContaining redacts any metadata key that contains one of these
+	// substrings, case-insensitively (e.g. "secret" matches "clientSecret").
+	RedactContaining []string
+
+	// RateLimitBypassWarn exempts Warn and above from the per-layer limits
+	// set with Logger.SetRateLimit.
+	RateLimitBypassWarn bool
+
+	// MaxCacheSize caps the number of package paths whose resolved layer
+	// is cached. Once full, the least recently used entry is evicted.
+	// Zero means unbounded.
+	MaxCacheSize int
+
+	// ResolutionDebug writes a line to stderr for each package whose layer
+	// is resolved rather than taken from the cache: the package path, the
+	// depth used and where it came from, or the layer inherited from a
+	// parent package, and the final layer. Use it to find out why a layer
+	// came out wrong.
+	ResolutionDebug bool
+
+	// TestMode turns on Logger.Test and Entry.Test. Test lines are written
+	// regardless of the logger's level; without TestMode they are dropped.
+	TestMode bool
+
+	skipRegexps []*regexp.Regexp // SkipPatterns, compiled by Validate
+	mainModule  string           // Main module path, detected by Validate for AutoModulePrefix
+}
+
+// fallbackLayer returns the layer for a package path that resolves to
+// nothing.
+func (c *Config) fallbackLayer() string {
+	if c.DefaultLayer == "" {
+		return string(LayerUnknown)
+	}
+	return string(c.DefaultLayer)
 }
 
 // packageConfig stores per-package layer configuration set via
@@ -37,6 +173,20 @@ type packageConfig struct {
 	explicitDepth *int    // Set via SetDepth()
 }
 
+// PackageLayerInfo is a copy of the settings made for one package with
+// SetLayerForPackage and SetDepth, as returned by Logger.Registry.
+type PackageLayerInfo struct {
+	// Layer is the explicit layer, which subpackages inherit. Only
+	// meaningful when HasLayer is set.
+	Layer    string
+	HasLayer bool
+
+	// Depth is the explicit depth, which applies to this package alone.
+	// Only meaningful when HasDepth is set.
+	Depth    int
+	HasDepth bool
+}
+
 // DefaultConfig returns a Config with sensible defaults for most Go projects.
 func DefaultConfig() Config {
 	return Config{
@@ -49,8 +199,11 @@ func DefaultConfig() Config {
 			"primary",
 			"secondary",
 		},
-		StrictMode:    strictMode,
-		AllowedLayers: nil,
+		StrictMode:       strictMode,
+		AllowedLayers:    nil,
+		TimeFormat:       TimeFormat,
+		AutoModulePrefix: true,
+		LayerSeparator:   "/",
 	}
 }
 
@@ -64,16 +217,89 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("StrictMode requires at least one AllowedLayers")
 	}
 
+	if c.LayerCase < LayerCaseUpper || c.LayerCase > LayerCaseOriginal {
+		return fmt.Errorf("LayerCase must be LayerCaseUpper, LayerCaseLower or LayerCaseOriginal, got %d", c.LayerCase)
+	}
+
+	if c.StrictModeAction < StrictPanic || c.StrictModeAction > StrictDrop {
+		return fmt.Errorf("StrictModeAction must be StrictPanic, StrictError, StrictWarn or StrictDrop, got %d", c.StrictModeAction)
+	}
+
+	if c.BufferSize < 0 {
+		return fmt.Errorf("BufferSize must be >= 0, got %d", c.BufferSize)
+	}
+
+	c.skipRegexps = nil
+	for _, pattern := range c.SkipPatterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return fmt.Errorf("SkipPatterns: invalid pattern %q: %w", pattern, err)
+		}
+		c.skipRegexps = append(c.skipRegexps, re)
+	}
+
+	c.mainModule = ""
+	if c.autoModulePrefix() {
+		c.mainModule = mainModulePath()
+	}
+
+	if c.MaxCacheSize < 0 {
+		return fmt.Errorf("MaxCacheSize must be >= 0, got %d", c.MaxCacheSize)
+	}
+
 	return nil
 }
 
-// ShouldSkipSegment checks if a package path segment should be filtered out.
+// modulePrefix returns the prefix to strip from package paths, without a
+// trailing slash.
+func (c *Config) modulePrefix() string {
+	if c.autoModulePrefix() {
+		return c.mainModule
+	}
+	return strings.TrimSuffix(c.ModulePrefix, "/")
+}
+
+// autoModulePrefix reports whether the prefix comes from the build info.
+func (c *Config) autoModulePrefix() bool {
+	return c.ModulePrefix == ModulePrefixAuto || (c.AutoModulePrefix && c.ModulePrefix == "")
+}
+
+// trimModulePrefix strips the module prefix from packagePath. Paths outside
+// the module, and the module's root package itself, are returned unchanged.
+func (c *Config) trimModulePrefix(packagePath string) string {
+	prefix := c.modulePrefix()
+	if prefix == "" {
+		return packagePath
+	}
+	if rest, ok := strings.CutPrefix(packagePath, prefix+"/"); ok {
+		return rest
+	}
+	return packagePath
+}
+
+// mainModulePath returns the main module path from the build info, or ""
+// if it is unavailable.
+func mainModulePath() string {
+	info, ok := readBuildInfo()
+	if !ok || info == nil {
+		return ""
+	}
+	return info.Main.Path
+}
+
+// ShouldSkipSegment checks if a package path segment should be filtered out,
+// either listed in SkipSegments or matching one of SkipPatterns.
 func (c *Config) ShouldSkipSegment(segment string) bool {
-	if c.SkipSegments == nil {
-		return false
+	if slices.Contains(c.SkipSegments, segment) {
+		return true
 	}
 
-	return slices.Contains(c.SkipSegments, segment)
+	for _, re := range c.skipRegexps {
+		if re.MatchString(segment) {
+			return true
+		}
+	}
+	return false
 }
 
 // IsLayerAllowed checks if a layer is permitted by the current configuration.
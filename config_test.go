@@ -20,6 +20,10 @@ func TestDefaultConfig(t *testing.T) {
 	if len(config.SkipSegments) == 0 {
 		t.Error("Expected default skip segments")
 	}
+
+	if !config.AutoModulePrefix {
+		t.Error("Expected AutoModulePrefix=true by default")
+	}
 }
 
 func TestConfigValidation(t *testing.T) {
@@ -57,6 +61,42 @@ func TestConfigValidation(t *testing.T) {
 			wantError: true,
 			errorMsg:  "requires at least one AllowedLayer",
 		},
+		{
+			name: "negative buffer size",
+			config: Config{
+				DefaultDepth: 3,
+				BufferSize:   -1,
+			},
+			wantError: true,
+			errorMsg:  "BufferSize must be >= 0",
+		},
+		{
+			name: "invalid skip pattern",
+			config: Config{
+				DefaultDepth: 3,
+				SkipPatterns: []string{"v[0-9"},
+			},
+			wantError: true,
+			errorMsg:  "SkipPatterns: invalid pattern",
+		},
+		{
+			name: "unknown layer case",
+			config: Config{
+				DefaultDepth: 3,
+				LayerCase:    LayerCase(7),
+			},
+			wantError: true,
+			errorMsg:  "LayerCase must be",
+		},
+		{
+			name: "negative max cache size",
+			config: Config{
+				DefaultDepth: 3,
+				MaxCacheSize: -1,
+			},
+			wantError: true,
+			errorMsg:  "MaxCacheSize must be >= 0",
+		},
 		{
 			name: "strict mode with allowed layers",
 			config: Config{
@@ -116,6 +156,39 @@ func TestShouldSkipSegment(t *testing.T) {
 	}
 }
 
+func TestShouldSkipSegmentPatterns(t *testing.T) {
+	config := Config{
+		SkipSegments:   []string{"internal"},
+		SkipPatterns:   []string{`^v[0-9]+$`, `_gen$`},
+		LayerSeparator: "/",
+	}
+	if err := config.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	tests := []struct {
+		segment string
+		want    bool
+	}{
+		{"internal", true},
+		{"v1", true},
+		{"v23", true},
+		{"proto_gen", true},
+		{"vendor", false},
+		{"api", false},
+	}
+
+	for _, tt := range tests {
+		if got := config.ShouldSkipSegment(tt.segment); got != tt.want {
+			t.Errorf("ShouldSkipSegment(%q) = %v, want %v", tt.segment, got, tt.want)
+		}
+	}
+
+	if got := extractLayer("myapp/internal/api/v2/handlers", 4, &config); got != "API/HANDLERS" {
+		t.Errorf("extractLayer() = %q, want %q", got, "API/HANDLERS")
+	}
+}
+
 func TestShouldSkipSegmentNilList(t *testing.T) {
 	config := Config{
 		SkipSegments: nil,
@@ -0,0 +1,81 @@
+package logr
+
+import (
+	"context"
+	"slices"
+)
+
+// ContextExtractor pulls request-scoped fields such as a request ID out of
+// a context.Context.
+type ContextExtractor func(ctx context.Context) map[string]any
+
+// RegisterContextExtractor adds fn to the extractors consulted by the Ctx
+// logging methods. Fields from later extractors win on key collisions.
+func (l *Logger) RegisterContextExtractor(fn ContextExtractor) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.extractors = append(l.extractors, fn)
+}
+
+// contextMetadata runs the registered extractors on ctx. It returns nil
+// for a nil context or when no fields are found.
+func (l *Logger) contextMetadata(ctx context.Context) *Metadata {
+	if ctx == nil {
+		return nil
+	}
+
+	l.mu.Lock()
+	extractors := l.extractors
+	l.mu.Unlock()
+
+	var meta *Metadata
+	for _, extract := range extractors {
+		fields := extract(ctx)
+		if len(fields) == 0 {
+			continue
+		}
+		if meta == nil {
+			meta = acquireMetadata()
+		}
+
+		// Sort so fields from a single map are written in a stable order
+		keys := make([]string, 0, len(fields))
+		for key := range fields {
+			keys = append(keys, key)
+		}
+		slices.Sort(keys)
+		for _, key := range keys {
+			meta.Add(key, fields[key])
+		}
+	}
+	return meta
+}
+
+// logCtx is log with fields from ctx. The level is checked first, so the
+// extractors don't run for lines that would be filtered out.
+func (l *Logger) logCtx(ctx context.Context, level Level, msg string) {
+	if !l.enabledFor("", level) {
+		return
+	}
+	l.log(level, msg, l.contextMetadata(ctx))
+}
+
+func (l *Logger) InfoCtx(ctx context.Context, msg string) {
+	l.logCtx(ctx, LevelInfo, msg)
+}
+
+func (l *Logger) ErrorCtx(ctx context.Context, msg string) {
+	l.logCtx(ctx, LevelError, msg)
+}
+
+func (l *Logger) DebugCtx(ctx context.Context, msg string) {
+	l.logCtx(ctx, LevelDebug, msg)
+}
+
+func (l *Logger) WarnCtx(ctx context.Context, msg string) {
+	l.logCtx(ctx, LevelWarn, msg)
+}
+
+func (l *Logger) TraceCtx(ctx context.Context, msg string) {
+	l.logCtx(ctx, LevelTrace, msg)
+}
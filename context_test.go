@@ -0,0 +1,93 @@
+package logr
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+type ctxKey string
+
+func TestCtxMethodsUseExtractors(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(&PlainTextFormatter{}, LevelInfo, DefaultConfig())
+	logger.SetOutput(&buf)
+
+	logger.RegisterContextExtractor(func(ctx context.Context) map[string]any {
+		fields := map[string]any{}
+		if id, ok := ctx.Value(ctxKey("requestID")).(string); ok {
+			fields["requestID"] = id
+		}
+		if user, ok := ctx.Value(ctxKey("userID")).(int); ok {
+			fields["userID"] = user
+		}
+		return fields
+	})
+
+	ctx := context.WithValue(context.Background(), ctxKey("requestID"), "req-1")
+	ctx = context.WithValue(ctx, ctxKey("userID"), 42)
+
+	logger.InfoCtx(ctx, "handled")
+
+	if !strings.Contains(buf.String(), "[CHEEZECAKEE/LOGR]") {
+		t.Errorf("expected layer of the calling package, got %q", buf.String())
+	}
+	if !strings.HasSuffix(buf.String(), "handled requestID=req-1 userID=42\n") {
+		t.Errorf("expected context fields in output, got %q", buf.String())
+	}
+}
+
+func TestCtxMethodsNilContext(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(&MockFormatter{}, LevelInfo, DefaultConfig())
+	logger.SetOutput(&buf)
+
+	called := false
+	logger.RegisterContextExtractor(func(ctx context.Context) map[string]any {
+		called = true
+		return nil
+	})
+
+	var ctx context.Context // nil
+	logger.ErrorCtx(ctx, "no context")
+
+	if called {
+		t.Error("expected extractors to be skipped for a nil context")
+	}
+	if buf.String() != "no context\n" {
+		t.Errorf("expected line to be logged, got %q", buf.String())
+	}
+}
+
+func TestCtxMethodsSkipExtractorsBelowLevel(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(&MockFormatter{}, LevelInfo, DefaultConfig())
+	logger.SetOutput(&buf)
+
+	calls := 0
+	logger.RegisterContextExtractor(func(ctx context.Context) map[string]any {
+		calls++
+		return map[string]any{"requestID": "req-1"}
+	})
+
+	ctx := context.Background()
+	logger.DebugCtx(ctx, "filtered by the global level")
+	if calls != 0 {
+		t.Errorf("extractor ran %d times for a filtered level, want 0", calls)
+	}
+
+	logger.SetLevelForLayer(Layer(logger.ResolveLayerFor(getCurrentPackage(1))), LevelError)
+	logger.WarnCtx(ctx, "filtered by the layer's level")
+	if calls != 0 {
+		t.Errorf("extractor ran %d times for a level filtered by the layer, want 0", calls)
+	}
+
+	logger.ErrorCtx(ctx, "written")
+	if calls != 1 {
+		t.Errorf("extractor ran %d times, want 1", calls)
+	}
+	if buf.String() != "written\n" {
+		t.Errorf("got %q, want only the enabled line", buf.String())
+	}
+}
@@ -0,0 +1,212 @@
+package logr
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// dedupKey is what makes two consecutive entries identical for
+// Config.DedupConsecutive.
+type dedupKey struct {
+	level   Level
+	layer   Layer
+	message string
+	fields  string // Metadata and Err, see dedupFields
+}
+
+func newDedupKey(entry LogEntry) dedupKey {
+	return dedupKey{
+		level:   entry.Level,
+		layer:   entry.Layer,
+		message: entry.Message,
+		fields:  dedupFields(entry),
+	}
+}
+
+// dedupFields renders an entry's metadata and error for comparison, so
+// repeats that differ only in their fields are all written.
+func dedupFields(entry LogEntry) string {
+	var b strings.Builder
+	if entry.Metadata != nil {
+		entry.Metadata.walk("", func(key string, value any) {
+			fmt.Fprintf(&b, "%s=%v\x00", key, value)
+		})
+	}
+	if entry.Err != nil {
+		b.WriteString("error=" + entry.Err.Error())
+	}
+	return b.String()
+}
+
+// deduper collapses runs of identical consecutive entries written to one
+// destination, for Config.DedupConsecutive. Each output and each sink has
+// its own, since a destination only sees the levels routed to it. Callers
+// hold writeMu.
+type deduper struct {
+	key      dedupKey
+	have     bool // key holds the previous entry
+	repeated int  // Entries dropped since the previous one was written
+}
+
+// observe records an entry and reports whether it repeats the previous one
+// and should be dropped. When it ends a run of repeats, hasSummary is set
+// and summary is the entry to write before it.
+func (d *deduper) observe(key dedupKey) (summary LogEntry, hasSummary, drop bool) {
+	if d.have && key == d.key {
+		d.repeated++
+		return LogEntry{}, false, true
+	}
+
+	summary, hasSummary = d.flush()
+	d.key, d.have = key, true
+	return summary, hasSummary, false
+}
+
+// flush returns the summary for a pending run of repeats, if any, and
+// starts counting afresh.
+func (d *deduper) flush() (LogEntry, bool) {
+	if d.repeated == 0 {
+		return LogEntry{}, false
+	}
+	times := "times"
+	if d.repeated == 1 {
+		times = "time"
+	}
+	summary := LogEntry{
+		Level:   d.key.level,
+		Layer:   d.key.layer,
+		Message: fmt.Sprintf("last message repeated %d %s", d.repeated, times),
+	}
+	d.repeated = 0
+	return summary, true
+}
+
+// newOutputDedup gives the outputs fresh dedup state, shared when they
+// are the same writer. Callers must hold l.mu.
+func (l *Logger) newOutputDedup() {
+	if !l.config.DedupConsecutive {
+		return
+	}
+	l.outDedup = &deduper{}
+	l.errOutDedup = l.outDedup
+	if !sameWriter(l.errOut, l.out) {
+		l.errOutDedup = &deduper{}
+	}
+}
+
+// addSinkDedup gives the sink just added its dedup state. Callers must
+// hold l.mu.
+func (l *Logger) addSinkDedup() {
+	if l.config.DedupConsecutive {
+		l.sinkDedup = append(l.sinkDedup, &deduper{})
+	}
+}
+
+// dedupFor picks the dedup state for the output a level goes to, like
+// writerFor. Callers must hold l.mu.
+func (l *Logger) dedupFor(level Level) *deduper {
+	if level.atLeast(LevelWarn) {
+		return l.errOutDedup
+	}
+	return l.outDedup
+}
+
+// deliver writes a formatted line to its destinations, with
+// Config.DedupConsecutive first collapsing it into each destination's
+// current run of repeats. Callers hold writeMu.
+func (l *Logger) deliver(entry LogEntry, formatted string) {
+	l.mu.Lock()
+	dest := l.destinations(entry.Level)
+	l.mu.Unlock()
+
+	if dest.dedup != nil {
+		dest = l.dedupe(dest, entry)
+	}
+	dest.write(entry, formatted)
+}
+
+// dedupe leaves out the destinations for which entry repeats the previous
+// line, and writes the summary to those for which it ends a run of
+// repeats.
+func (l *Logger) dedupe(dest destinations, entry LogEntry) destinations {
+	key := newDedupKey(entry)
+
+	summary, hasSummary, drop := dest.dedup.observe(key)
+	if hasSummary {
+		l.writeSummary(summary, dest.primary, nil, dest.handleError)
+	}
+	if drop {
+		dest.primary = nil
+	}
+
+	var sinks []Sink
+	for i, sink := range dest.sinks {
+		summary, hasSummary, drop := dest.sinkDedup[i].observe(key)
+		if hasSummary {
+			l.writeSummary(summary, nil, sink, dest.handleError)
+		}
+		if !drop {
+			sinks = append(sinks, sink)
+		}
+	}
+	dest.sinks = sinks
+	return dest
+}
+
+// flushDedup writes the summaries of pending runs of repeats, for Close
+// and before SetOutputs replaces the outputs. Callers hold writeMu.
+func (l *Logger) flushDedup() {
+	if !l.config.DedupConsecutive {
+		return
+	}
+
+	l.mu.Lock()
+	out, errOut := l.out, l.errOut
+	outDedup, errOutDedup := l.outDedup, l.errOutDedup
+	sinks, sinkDedup := l.sinks[:len(l.sinks):len(l.sinks)], l.sinkDedup[:len(l.sinkDedup):len(l.sinkDedup)]
+	handleError := destinations{errorHandler: l.errorHandler}.handleError
+	l.mu.Unlock()
+
+	if summary, ok := outDedup.flush(); ok {
+		l.writeSummary(summary, out, nil, handleError)
+	}
+	if errOutDedup != outDedup {
+		if summary, ok := errOutDedup.flush(); ok {
+			l.writeSummary(summary, errOut, nil, handleError)
+		}
+	}
+	for i, sink := range sinks {
+		if summary, ok := sinkDedup[i].flush(); ok {
+			l.writeSummary(summary, nil, sink, handleError)
+		}
+	}
+}
+
+// writeSummary formats a "last message repeated" entry and writes it to
+// w, or to sink when w is nil. It skips hooks and processors, which saw
+// every repeat already.
+func (l *Logger) writeSummary(summary LogEntry, w io.Writer, sink Sink, handleError func(error)) {
+	summary.Timestamp = nowFunc()
+	if l.config.UTC {
+		summary.Timestamp = summary.Timestamp.UTC()
+	}
+	summary.timeFormat = l.config.TimeFormat
+	summary.escapeNewlines = l.config.EscapeNewlines
+
+	formatted, err := l.format(summary)
+	if err != nil {
+		l.reportError(err)
+		return
+	}
+
+	if w != nil {
+		if err := writeLine(w, summary.Level, formatted); err != nil {
+			handleError(fmt.Errorf("logr: write to output: %w", err))
+		}
+		return
+	}
+	if err := sink.Write(summary, formatted); err != nil {
+		handleError(fmt.Errorf("logr: write to sink: %w", err))
+	}
+}
@@ -0,0 +1,163 @@
+package logr
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func dedupConfig() Config {
+	config := DefaultConfig()
+	config.DedupConsecutive = true
+	return config
+}
+
+func TestDedupConsecutive(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(&MockFormatter{}, LevelInfo, dedupConfig())
+	logger.SetOutput(&buf)
+
+	for range 58 {
+		logger.Info("connection lost")
+	}
+	logger.Info("reconnected")
+	logger.Info("reconnected")
+	logger.Warn("reconnected") // Different level, not a repeat
+	logger.Info("done")
+
+	want := strings.Join([]string{
+		"connection lost",
+		"last message repeated 57 times",
+		"reconnected",
+		"last message repeated 1 time",
+		"reconnected",
+		"done",
+	}, "\n") + "\n"
+	if buf.String() != want {
+		t.Errorf("got:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
+
+func TestDedupConsecutiveSummaryKeepsLevelAndLayer(t *testing.T) {
+	logger := New(JSONFormatter{}, LevelInfo, dedupConfig())
+	var buf bytes.Buffer
+	logger.SetOutput(&buf)
+
+	db := logger.WithLayer(LayerDB)
+	db.Error("query failed")
+	db.Error("query failed")
+	db.Info("query ok")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("got %d lines, want 3:\n%s", len(lines), buf.String())
+	}
+	summary := lines[1]
+	if !strings.Contains(summary, `"level":"ERROR"`) || !strings.Contains(summary, `"layer":"DB"`) ||
+		!strings.Contains(summary, `"message":"last message repeated 1 time"`) {
+		t.Errorf("unexpected summary %s", summary)
+	}
+}
+
+func TestDedupConsecutiveFlushesOnClose(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(&MockFormatter{}, LevelInfo, dedupConfig())
+	logger.SetOutput(&buf)
+
+	logger.Info("tick")
+	logger.Info("tick")
+	logger.Info("tick")
+	if err := logger.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := "tick\nlast message repeated 2 times\n"; buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+}
+
+func TestDedupConsecutiveAsync(t *testing.T) {
+	var buf bytes.Buffer
+	config := dedupConfig()
+	config.Async = true
+	logger := New(&MockFormatter{}, LevelInfo, config)
+	logger.SetOutput(&buf)
+
+	for range 5 {
+		logger.Info("tick")
+	}
+	logger.Close()
+
+	if want := "tick\nlast message repeated 4 times\n"; buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+}
+
+func TestDedupConsecutiveOff(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(&MockFormatter{}, LevelInfo, DefaultConfig())
+	logger.SetOutput(&buf)
+
+	logger.Info("tick")
+	logger.Info("tick")
+	logger.Close()
+
+	if want := "tick\ntick\n"; buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+}
+
+func TestDedupConsecutivePerDestination(t *testing.T) {
+	logger := New(&MockFormatter{}, LevelInfo, dedupConfig())
+	var out, errOut, sink bytes.Buffer
+	logger.SetOutputs(&out, &errOut)
+	logger.AddSink(&sink)
+
+	logger.Info("tick")
+	logger.Warn("flapping") // Only reaches errOut and the sink
+	logger.Info("tick")
+	logger.Close()
+
+	if want := "tick\nlast message repeated 1 time\n"; out.String() != want {
+		t.Errorf("out = %q, want %q", out.String(), want)
+	}
+	if want := "flapping\n"; errOut.String() != want {
+		t.Errorf("errOut = %q, want %q", errOut.String(), want)
+	}
+	if want := "tick\nflapping\ntick\n"; sink.String() != want {
+		t.Errorf("sink = %q, want %q", sink.String(), want)
+	}
+}
+
+func TestDedupConsecutiveComparesMetadata(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(&MockFormatter{}, LevelInfo, dedupConfig())
+	logger.SetOutput(&buf)
+
+	logger.Infow("retry", "attempt", 1)
+	logger.Infow("retry", "attempt", 2)
+	logger.Infow("retry", "attempt", 2)
+	logger.Close()
+
+	if want := "retry\nretry\nlast message repeated 1 time\n"; buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+}
+
+func TestDedupConsecutiveFlushesOnSetOutput(t *testing.T) {
+	var first, second bytes.Buffer
+	logger := New(&MockFormatter{}, LevelInfo, dedupConfig())
+	logger.SetOutput(&first)
+
+	logger.Info("tick")
+	logger.Info("tick")
+	logger.SetOutput(&second)
+	logger.Info("tick")
+
+	if want := "tick\nlast message repeated 1 time\n"; first.String() != want {
+		t.Errorf("first = %q, want %q", first.String(), want)
+	}
+	if want := "tick\n"; second.String() != want {
+		t.Errorf("second = %q, want %q", second.String(), want)
+	}
+}
@@ -1,7 +1,16 @@
 // Package logr
 package logr
 
-import "time"
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// nowFunc is the clock used to timestamp new entries. Tests replace it to
+// get deterministic timestamps.
+var nowFunc = time.Now
 
 type LogEntry struct {
 	Level     Level
@@ -9,20 +18,57 @@ type LogEntry struct {
 	Message   string
 	Timestamp time.Time
 	Metadata  *Metadata
+	Err       error  // Error logged with ErrorErr, rendered as its own field
+	File      string // Call site file, set when Config.IncludeCaller is on
+	Line      int    // Call site line, set when Config.IncludeCaller is on
+	Stack     string // Stack trace, set when Config.CaptureStackOnError is on
+
+	timeFormat     string // Config.TimeFormat of the logger that created the entry
+	escapeNewlines bool   // Config.EscapeNewlines of the logger that created the entry
+}
+
+// FormattedTime renders Timestamp using the logger's Config.TimeFormat,
+// falling back to TimeFormat (RFC3339) for entries built outside a logger.
+func (l *LogEntry) FormattedTime() string {
+	switch l.timeFormat {
+	case "":
+		return l.Timestamp.Format(TimeFormat)
+	case TimeFormatUnix:
+		return strconv.FormatInt(l.Timestamp.Unix(), 10)
+	case TimeFormatUnixMs:
+		return strconv.FormatInt(l.Timestamp.UnixMilli(), 10)
+	default:
+		return l.Timestamp.Format(l.timeFormat)
+	}
+}
+
+// Caller returns the call site as "file:line", or "" when not recorded.
+func (l *LogEntry) Caller() string {
+	if l.File == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s:%d", l.File, l.Line)
+}
+
+// errorText returns Err's message, or "" when there is no error.
+func (l *LogEntry) errorText() string {
+	if l.Err == nil {
+		return ""
+	}
+	return l.Err.Error()
 }
 
 func NewEntry(level Level, layer Layer, msg string, meta ...Metadata) *LogEntry {
+	// Metadata stays nil without fields; formatters treat nil as none
 	var metadata *Metadata
 	if len(meta) > 0 {
 		metadata = &meta[0]
-	} else {
-		metadata = nil
 	}
 	return &LogEntry{
 		Level:     level,
 		Layer:     layer,
 		Message:   msg,
-		Timestamp: time.Now(),
+		Timestamp: nowFunc(),
 		Metadata:  metadata,
 	}
 }
@@ -33,3 +79,200 @@ func (l *LogEntry) AddMetadata(key string, value any) {
 	}
 	l.Metadata.Add(key, value)
 }
+
+// Entry carries structured fields bound to a Logger. It is created with
+// Logger.WithField or Logger.WithFields and is safe to reuse: adding more
+// fields returns a new Entry rather than modifying the original.
+type Entry struct {
+	logger   *Logger
+	metadata *Metadata
+	layer    Layer // Set by WithLayer; empty means resolve from the caller
+}
+
+// WithField returns an Entry that attaches key=value to every log it writes.
+func (l *Logger) WithField(key string, value any) *Entry {
+	return (&Entry{logger: l, metadata: NewMetadata()}).WithField(key, value)
+}
+
+// WithFields returns an Entry that attaches all of fields to every log it writes.
+func (l *Logger) WithFields(fields map[string]any) *Entry {
+	return (&Entry{logger: l, metadata: NewMetadata()}).WithFields(fields)
+}
+
+// WithField returns a copy of the Entry with key=value added.
+func (e *Entry) WithField(key string, value any) *Entry {
+	meta := e.metadata.Clone()
+	meta.Add(key, value)
+	return &Entry{logger: e.logger, metadata: meta, layer: e.layer}
+}
+
+// WithFields returns a copy of the Entry with all of fields added.
+func (e *Entry) WithFields(fields map[string]any) *Entry {
+	meta := e.metadata.Clone()
+	for k, v := range fields {
+		meta.Add(k, v)
+	}
+	return &Entry{logger: e.logger, metadata: meta, layer: e.layer}
+}
+
+// WithLayer returns an Entry that writes under layer instead of the layer
+// resolved from the calling package, e.g. to tag a line from an HTTP
+// handler as AUDIT. The layer is used as given, like one set with
+// SetLayerForPackage, and combines with fields:
+//
+//	logger.WithLayer("AUDIT").WithField("userID", 5).Info("role changed")
+func (l *Logger) WithLayer(layer Layer) *Entry {
+	return &Entry{logger: l, metadata: NewMetadata(), layer: layer}
+}
+
+// WithLayer returns a copy of the Entry that writes under layer. An empty
+// layer goes back to resolving it from the calling package.
+func (e *Entry) WithLayer(layer Layer) *Entry {
+	return &Entry{logger: e.logger, metadata: e.metadata, layer: layer}
+}
+
+// Enabled is Logger.Enabled for lines written through the Entry, using
+// its layer when one was set with WithLayer.
+func (e *Entry) Enabled(level Level) bool {
+	return e.logger.enabledFor(e.layer, level)
+}
+
+// ComponentKey is the field set by Named.
+const ComponentKey = "component"
+
+// Named returns an Entry whose lines carry component=name, for telling
+// apart parts of a package when its layer is too coarse:
+//
+//	auth := logger.Named("auth")
+//	auth.Info("token issued") // ... token issued component=auth
+func (l *Logger) Named(name string) *Entry {
+	return (&Entry{logger: l, metadata: NewMetadata()}).Named(name)
+}
+
+// Named returns a copy of the Entry with name appended to its component,
+// separated by a dot: logger.Named("auth").Named("oauth") logs
+// component=auth.oauth. An empty name returns the Entry unchanged.
+func (e *Entry) Named(name string) *Entry {
+	if name == "" {
+		return e
+	}
+	if parent, ok := e.metadata.GetString(ComponentKey); ok && parent != "" {
+		name = parent + "." + name
+	}
+	return e.WithField(ComponentKey, name)
+}
+
+// Field names set by WithError.
+const (
+	ErrorKey      = "error"
+	ErrorTypeKey  = "errorType"
+	ErrorChainKey = "errorChain"
+)
+
+// WithError returns an Entry carrying err's message and type. A nil err
+// adds nothing.
+func (l *Logger) WithError(err error) *Entry {
+	return (&Entry{logger: l, metadata: NewMetadata()}).WithError(err)
+}
+
+// WithError returns a copy of the Entry with err recorded as the ErrorKey
+// (message) and ErrorTypeKey (Go type) fields. When err wraps other errors,
+// their messages are listed under ErrorChainKey, outermost first. A nil
+// err returns the Entry unchanged.
+func (e *Entry) WithError(err error) *Entry {
+	if err == nil {
+		return e
+	}
+
+	meta := e.metadata.Clone()
+	meta.Add(ErrorKey, err.Error())
+	meta.Add(ErrorTypeKey, fmt.Sprintf("%T", err))
+
+	var chain []string
+	for wrapped := errors.Unwrap(err); wrapped != nil; wrapped = errors.Unwrap(wrapped) {
+		chain = append(chain, wrapped.Error())
+	}
+	if len(chain) > 0 {
+		meta.Add(ErrorChainKey, chain)
+	}
+
+	return &Entry{logger: e.logger, metadata: meta, layer: e.layer}
+}
+
+// fields returns a copy of the Entry's fields for one log call, or nil
+// when it has none so the line carries no empty Metadata.
+func (e *Entry) fields() *Metadata {
+	if len(e.metadata.Data) == 0 {
+		return nil
+	}
+	return e.metadata.clonePooled()
+}
+
+func (e *Entry) Info(msg string) {
+	e.logger.logAs(e.layer, LevelInfo, msg, e.fields())
+}
+
+func (e *Entry) Error(msg string) {
+	e.logger.logAs(e.layer, LevelError, msg, e.fields())
+}
+
+// ErrorErr is Logger.ErrorErr with the Entry's fields.
+func (e *Entry) ErrorErr(msg string, err error) {
+	e.logger.logErrAs(e.layer, LevelError, msg, err, e.fields())
+}
+
+func (e *Entry) Debug(msg string) {
+	e.logger.logAs(e.layer, LevelDebug, msg, e.fields())
+}
+
+func (e *Entry) Warn(msg string) {
+	e.logger.logAs(e.layer, LevelWarn, msg, e.fields())
+}
+
+func (e *Entry) Test(msg string) {
+	e.logger.logAs(e.layer, LevelTest, msg, e.fields())
+}
+
+func (e *Entry) Trace(msg string) {
+	e.logger.logAs(e.layer, LevelTrace, msg, e.fields())
+}
+
+func (e *Entry) Errorf(format string, args ...any) {
+	e.logger.logAs(e.layer, LevelError, fmt.Sprintf(format, args...), e.fields())
+}
+
+func (e *Entry) Infof(format string, args ...any) {
+	e.logger.logAs(e.layer, LevelInfo, fmt.Sprintf(format, args...), e.fields())
+}
+
+func (e *Entry) Debugf(format string, args ...any) {
+	e.logger.logAs(e.layer, LevelDebug, fmt.Sprintf(format, args...), e.fields())
+}
+
+func (e *Entry) Warnf(format string, args ...any) {
+	e.logger.logAs(e.layer, LevelWarn, fmt.Sprintf(format, args...), e.fields())
+}
+
+func (e *Entry) Tracef(format string, args ...any) {
+	e.logger.logAs(e.layer, LevelTrace, fmt.Sprintf(format, args...), e.fields())
+}
+
+func (e *Entry) Errorln(args ...any) {
+	e.logger.logAs(e.layer, LevelError, sprintln(args...), e.fields())
+}
+
+func (e *Entry) Infoln(args ...any) {
+	e.logger.logAs(e.layer, LevelInfo, sprintln(args...), e.fields())
+}
+
+func (e *Entry) Debugln(args ...any) {
+	e.logger.logAs(e.layer, LevelDebug, sprintln(args...), e.fields())
+}
+
+func (e *Entry) Warnln(args ...any) {
+	e.logger.logAs(e.layer, LevelWarn, sprintln(args...), e.fields())
+}
+
+func (e *Entry) Traceln(args ...any) {
+	e.logger.logAs(e.layer, LevelTrace, sprintln(args...), e.fields())
+}
@@ -0,0 +1,332 @@
+package logr
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoggerWithField(t *testing.T) {
+	resetLogger()
+
+	var buf bytes.Buffer
+	logger := Init(&PlainTextFormatter{}, LevelInfo, nil)
+	logger.SetOutput(&buf)
+
+	logger.WithField("userID", 42).Info("User logged in")
+
+	output := buf.String()
+	if !strings.Contains(output, "User logged in") || !strings.Contains(output, "userID=42") {
+		t.Errorf("expected message and field in output, got %q", output)
+	}
+	if !strings.Contains(output, "[CHEEZECAKEE/LOGR]") {
+		t.Errorf("expected layer of calling package, got %q", output)
+	}
+}
+
+func TestLoggerWithFields(t *testing.T) {
+	resetLogger()
+
+	var buf bytes.Buffer
+	logger := Init(JSONFormatter{}, LevelInfo, nil)
+	logger.SetOutput(&buf)
+
+	logger.WithFields(map[string]any{"requestID": "abc", "status": 200}).Errorf("failed after %d retries", 3)
+
+	output := buf.String()
+	if !strings.Contains(output, `"requestID":"abc"`) || !strings.Contains(output, `"status":200`) {
+		t.Errorf("expected fields in JSON output, got %q", output)
+	}
+	if !strings.Contains(output, "failed after 3 retries") {
+		t.Errorf("expected formatted message, got %q", output)
+	}
+}
+
+func TestEntryWithFieldDoesNotMutateParent(t *testing.T) {
+	resetLogger()
+
+	logger := Init(&PlainTextFormatter{}, LevelInfo, nil)
+
+	parent := logger.WithField("a", 1)
+	child := parent.WithField("b", 2)
+
+	if _, ok := parent.metadata.Get("b"); ok {
+		t.Error("expected parent entry to be unchanged by child WithField")
+	}
+	if _, ok := child.metadata.Get("a"); !ok {
+		t.Error("expected child entry to inherit parent fields")
+	}
+}
+
+func TestNamedComposesComponent(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(&PlainTextFormatter{}, LevelInfo, DefaultConfig())
+	logger.SetOutput(&buf)
+
+	auth := logger.Named("auth")
+	oauth := auth.Named("oauth").WithField("client", "web")
+
+	auth.Info("token issued")
+	oauth.Info("redirect")
+	logger.WithField("a", 1).Named("jobs").Named("").Info("queued")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	wants := []string{
+		"token issued component=auth",
+		"redirect component=auth.oauth client=web",
+		"queued a=1 component=jobs",
+	}
+	for i, want := range wants {
+		if !strings.HasSuffix(lines[i], want) {
+			t.Errorf("line %d = %q, want it to end with %q", i, lines[i], want)
+		}
+	}
+
+	if got, _ := auth.metadata.GetString(ComponentKey); got != "auth" {
+		t.Errorf("parent component = %q after Named on a child, want auth", got)
+	}
+}
+
+func TestEntryRespectsLevel(t *testing.T) {
+	resetLogger()
+
+	var buf bytes.Buffer
+	logger := Init(&PlainTextFormatter{}, LevelWarn, nil)
+	logger.SetOutput(&buf)
+
+	logger.WithField("k", "v").Info("filtered")
+
+	if buf.Len() != 0 {
+		t.Errorf("expected no output below level, got %q", buf.String())
+	}
+}
+
+func TestLogEntryFormattedTime(t *testing.T) {
+	ts := time.Date(2025, 9, 29, 12, 30, 45, 123000000, time.UTC)
+
+	tests := []struct {
+		timeFormat string
+		want       string
+	}{
+		{"", "2025-09-29T12:30:45Z"},
+		{time.RFC3339Nano, "2025-09-29T12:30:45.123Z"},
+		{"15:04:05", "12:30:45"},
+		{TimeFormatUnix, "1759149045"},
+		{TimeFormatUnixMs, "1759149045123"},
+	}
+
+	for _, tt := range tests {
+		entry := LogEntry{Timestamp: ts, timeFormat: tt.timeFormat}
+		if got := entry.FormattedTime(); got != tt.want {
+			t.Errorf("FormattedTime() with %q = %q, want %q", tt.timeFormat, got, tt.want)
+		}
+	}
+}
+
+func TestLoggerTimeFormatConfig(t *testing.T) {
+	config := DefaultConfig()
+	config.TimeFormat = TimeFormatUnix
+
+	var buf bytes.Buffer
+	logger := New(JSONFormatter{}, LevelInfo, config)
+	logger.SetOutput(&buf)
+
+	logger.Info("epoch")
+
+	if !strings.Contains(buf.String(), `"timestamp":1`) {
+		t.Errorf("expected numeric epoch timestamp, got %q", buf.String())
+	}
+}
+
+func TestLoggerUTCConfig(t *testing.T) {
+	config := DefaultConfig()
+	config.UTC = true
+
+	var buf bytes.Buffer
+	logger := New(JSONFormatter{}, LevelInfo, config)
+	logger.SetOutput(&buf)
+
+	logger.Info("utc")
+
+	var decoded struct {
+		Timestamp string `json:"timestamp"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("invalid JSON %q: %v", buf.String(), err)
+	}
+	if !strings.HasSuffix(decoded.Timestamp, "Z") {
+		t.Errorf("expected UTC timestamp ending in Z, got %q", decoded.Timestamp)
+	}
+}
+
+func TestLoggerEscapeNewlinesConfig(t *testing.T) {
+	config := DefaultConfig()
+	config.EscapeNewlines = true
+
+	var buf bytes.Buffer
+	logger := New(&PlainTextFormatter{}, LevelInfo, config)
+	logger.SetOutput(&buf)
+
+	logger.WithField("query", "SELECT 1\r\nFROM t").Info("first\nsecond")
+
+	line := buf.String()
+	if strings.Count(line, "\n") != 1 {
+		t.Fatalf("expected one physical line, got %q", line)
+	}
+	if !strings.Contains(line, `first\nsecond query=SELECT 1\r\nFROM t`) {
+		t.Errorf("expected escaped newlines, got %q", line)
+	}
+}
+
+func TestPlainTextFormatterKeepsNewlinesByDefault(t *testing.T) {
+	entry := NewEntry(LevelInfo, LayerHTTP, "first\nsecond")
+
+	if got := (&PlainTextFormatter{}).Format(*entry); !strings.Contains(got, "first\nsecond") {
+		t.Errorf("expected the raw newline without EscapeNewlines, got %q", got)
+	}
+}
+
+// setNow pins nowFunc to ts for the duration of the test.
+func setNow(t *testing.T, ts time.Time) {
+	t.Helper()
+	orig := nowFunc
+	nowFunc = func() time.Time { return ts }
+	t.Cleanup(func() { nowFunc = orig })
+}
+
+func TestLoggerInfoWithFixedClock(t *testing.T) {
+	setNow(t, time.Date(2025, 9, 29, 12, 0, 0, 0, time.UTC))
+
+	var buf bytes.Buffer
+	logger := New(&PlainTextFormatter{}, LevelInfo, DefaultConfig())
+	logger.SetOutput(&buf)
+
+	logger.Info("fixed time")
+
+	want := "[INFO] [CHEEZECAKEE/LOGR] [2025-09-29T12:00:00Z] fixed time\n"
+	if buf.String() != want {
+		t.Errorf("expected %q, got %q", want, buf.String())
+	}
+}
+
+func TestLoggerWithError(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(JSONFormatter{}, LevelInfo, DefaultConfig())
+	logger.SetOutput(&buf)
+
+	base := &os.PathError{Op: "open", Path: "/etc/app.conf", Err: os.ErrNotExist}
+	err := fmt.Errorf("loading config: %w", base)
+
+	logger.WithError(err).Error("startup failed")
+
+	var decoded struct {
+		Metadata map[string]any `json:"metadata"`
+	}
+	if jerr := json.Unmarshal(buf.Bytes(), &decoded); jerr != nil {
+		t.Fatalf("invalid JSON %q: %v", buf.String(), jerr)
+	}
+
+	if decoded.Metadata[ErrorKey] != err.Error() {
+		t.Errorf("expected error message, got %v", decoded.Metadata[ErrorKey])
+	}
+	if decoded.Metadata[ErrorTypeKey] != "*fmt.wrapError" {
+		t.Errorf("expected error type, got %v", decoded.Metadata[ErrorTypeKey])
+	}
+	chain, _ := decoded.Metadata[ErrorChainKey].([]any)
+	if len(chain) != 2 || chain[0] != base.Error() || chain[1] != os.ErrNotExist.Error() {
+		t.Errorf("expected unwrapped chain, got %v", decoded.Metadata[ErrorChainKey])
+	}
+}
+
+func TestLoggerWithErrorNil(t *testing.T) {
+	logger := New(&PlainTextFormatter{}, LevelInfo, DefaultConfig())
+
+	entry := logger.WithError(nil)
+	if len(entry.metadata.Data) != 0 {
+		t.Errorf("expected no fields for a nil error, got %v", entry.metadata.Data)
+	}
+
+	plain := logger.WithField("k", "v").WithError(errors.New("simple"))
+	if _, ok := plain.metadata.Get(ErrorChainKey); ok {
+		t.Error("expected no chain for an unwrapped error")
+	}
+}
+
+func TestEntriesWithoutFieldsHaveNilMetadata(t *testing.T) {
+	if entry := NewEntry(LevelInfo, LayerHTTP, "no fields"); entry.Metadata != nil {
+		t.Errorf("NewEntry() Metadata = %v, want nil", entry.Metadata)
+	}
+
+	logger := New(&PlainTextFormatter{}, LevelInfo, DefaultConfig())
+	logger.SetOutput(&bytes.Buffer{})
+
+	var captured []LogEntry
+	logger.AddHook(func(entry LogEntry) {
+		captured = append(captured, entry)
+	})
+
+	logger.Info("plain")
+	logger.Infow("no pairs")
+	logger.WithFields(map[string]any{}).Info("empty entry")
+	slog.New(NewSlogHandler(logger)).Info("no attrs")
+
+	if len(captured) != 4 {
+		t.Fatalf("captured %d entries, want 4", len(captured))
+	}
+	for _, entry := range captured {
+		if entry.Metadata != nil {
+			t.Errorf("%q: Metadata = %v, want nil", entry.Message, entry.Metadata.Data)
+		}
+	}
+}
+
+func TestWithLayerOverridesResolvedLayer(t *testing.T) {
+	logger, sink := newMemoryTestLogger()
+
+	logger.WithLayer("AUDIT").Info("role changed")
+	logger.WithLayer("AUDIT").WithField("userID", 5).Warn("with fields after")
+	logger.WithField("userID", 6).WithLayer("AUDIT").Error("with fields before")
+	logger.WithLayer("AUDIT").WithLayer("").Info("back to resolved")
+	logger.Info("plain")
+
+	entries := sink.Entries()
+	if len(entries) != 5 {
+		t.Fatalf("got %d entries, want 5", len(entries))
+	}
+	for _, entry := range entries[:3] {
+		if entry.Layer != "AUDIT" {
+			t.Errorf("%q: layer = %q, want AUDIT", entry.Message, entry.Layer)
+		}
+	}
+	if got, _ := entries[1].Metadata.Get("userID"); got != 5 {
+		t.Errorf("userID = %v, want 5", got)
+	}
+	if got, _ := entries[2].Metadata.Get("userID"); got != 6 {
+		t.Errorf("userID = %v, want 6", got)
+	}
+	if entries[3].Layer != entries[4].Layer || entries[3].Layer == "AUDIT" {
+		t.Errorf("expected resolved layers, got %q and %q", entries[3].Layer, entries[4].Layer)
+	}
+}
+
+func TestWithLayerReportsCaller(t *testing.T) {
+	config := DefaultConfig()
+	config.IncludeCaller = true
+	logger := New(&PlainTextFormatter{}, LevelInfo, config)
+	logger.SetOutput(&bytes.Buffer{})
+	sink := &MemorySink{}
+	logger.AddEntrySink(sink)
+
+	logger.WithLayer("AUDIT").Info("role changed")
+
+	entry, _ := sink.LastEntry()
+	if entry.File != "entry_test.go" {
+		t.Errorf("File = %q, want entry_test.go", entry.File)
+	}
+}
@@ -0,0 +1,102 @@
+package logr
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Environment variables read by ConfigFromEnv.
+const (
+	EnvLevel        = "LOGR_LEVEL"         // Level name, e.g. "debug"
+	EnvFormat       = "LOGR_FORMAT"        // "plain" or "json"
+	EnvDepth        = "LOGR_DEPTH"         // Config.DefaultDepth
+	EnvSkipSegments = "LOGR_SKIP_SEGMENTS" // Comma-separated Config.SkipSegments
+	EnvStrict       = "LOGR_STRICT"        // Config.StrictMode, e.g. "true" or "1"
+)
+
+// ConfigFromEnv builds the arguments for InitWithConfig from the LOGR_*
+// environment variables, so the same binary can log differently in each
+// environment without recompiling:
+//
+//	config, formatter, level, err := logr.ConfigFromEnv()
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	logr.InitWithConfig(formatter, level, config)
+//
+// Variables that are unset or empty keep the defaults: DefaultConfig,
+// PlainTextFormatter and LevelInfo. LOGR_SKIP_SEGMENTS replaces the
+// default segments, and set to an empty string skips none. With
+// LOGR_STRICT, fill in Config.AllowedLayers before passing config on.
+//
+// Malformed values are all reported in the returned error, and the
+// setting they were meant for keeps its default.
+func ConfigFromEnv() (Config, Formatter, Level, error) {
+	config := DefaultConfig()
+	var formatter Formatter = &PlainTextFormatter{}
+	level := LevelInfo
+	var errs []error
+
+	if v := envValue(EnvLevel); v != "" {
+		parsed, err := ParseLevel(v)
+		if err != nil {
+			errs = append(errs, envError(EnvLevel, err))
+		} else {
+			level = parsed
+		}
+	}
+
+	if v := envValue(EnvFormat); v != "" {
+		switch strings.ToLower(v) {
+		case "plain":
+		case "json":
+			formatter = JSONFormatter{}
+		default:
+			errs = append(errs, envError(EnvFormat, fmt.Errorf("unknown format %q: expected plain or json", v)))
+		}
+	}
+
+	if v := envValue(EnvDepth); v != "" {
+		depth, err := strconv.Atoi(v)
+		switch {
+		case err != nil:
+			errs = append(errs, envError(EnvDepth, fmt.Errorf("invalid depth %q: expected an integer", v)))
+		case depth < 0:
+			errs = append(errs, envError(EnvDepth, fmt.Errorf("depth must be >= 0, got %d", depth)))
+		default:
+			config.DefaultDepth = depth
+		}
+	}
+
+	if v, ok := os.LookupEnv(EnvSkipSegments); ok {
+		config.SkipSegments = nil
+		for _, seg := range strings.Split(v, ",") {
+			if seg = strings.TrimSpace(seg); seg != "" {
+				config.SkipSegments = append(config.SkipSegments, seg)
+			}
+		}
+	}
+
+	if v := envValue(EnvStrict); v != "" {
+		strict, err := strconv.ParseBool(v)
+		if err != nil {
+			errs = append(errs, envError(EnvStrict, fmt.Errorf("invalid boolean %q", v)))
+		} else {
+			config.StrictMode = strict
+		}
+	}
+
+	return config, formatter, level, errors.Join(errs...)
+}
+
+// envValue returns the trimmed value of the environment variable key.
+func envValue(key string) string {
+	return strings.TrimSpace(os.Getenv(key))
+}
+
+func envError(key string, err error) error {
+	return fmt.Errorf("logr: %s: %w", key, err)
+}
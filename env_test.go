@@ -0,0 +1,91 @@
+package logr
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestConfigFromEnvDefaults(t *testing.T) {
+	for _, key := range []string{EnvLevel, EnvFormat, EnvDepth, EnvStrict} {
+		t.Setenv(key, "")
+	}
+
+	config, formatter, level, err := ConfigFromEnv()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(config, DefaultConfig()) {
+		t.Errorf("config = %+v, want DefaultConfig()", config)
+	}
+	if _, ok := formatter.(*PlainTextFormatter); !ok {
+		t.Errorf("formatter = %T, want *PlainTextFormatter", formatter)
+	}
+	if level != LevelInfo {
+		t.Errorf("level = %s, want INFO", level)
+	}
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	t.Setenv(EnvLevel, "debug")
+	t.Setenv(EnvFormat, "JSON")
+	t.Setenv(EnvDepth, "3")
+	t.Setenv(EnvSkipSegments, "internal, adapters,,")
+	t.Setenv(EnvStrict, "true")
+
+	config, formatter, level, err := ConfigFromEnv()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if level != LevelDebug {
+		t.Errorf("level = %s, want DEBUG", level)
+	}
+	if _, ok := formatter.(JSONFormatter); !ok {
+		t.Errorf("formatter = %T, want JSONFormatter", formatter)
+	}
+	if config.DefaultDepth != 3 {
+		t.Errorf("DefaultDepth = %d, want 3", config.DefaultDepth)
+	}
+	if want := []string{"internal", "adapters"}; !reflect.DeepEqual(config.SkipSegments, want) {
+		t.Errorf("SkipSegments = %q, want %q", config.SkipSegments, want)
+	}
+	if !config.StrictMode {
+		t.Error("expected StrictMode")
+	}
+}
+
+func TestConfigFromEnvEmptySkipSegments(t *testing.T) {
+	t.Setenv(EnvSkipSegments, "")
+
+	config, _, _, err := ConfigFromEnv()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(config.SkipSegments) != 0 {
+		t.Errorf("SkipSegments = %q, want none", config.SkipSegments)
+	}
+}
+
+func TestConfigFromEnvMalformed(t *testing.T) {
+	t.Setenv(EnvLevel, "loud")
+	t.Setenv(EnvFormat, "yaml")
+	t.Setenv(EnvDepth, "-1")
+	t.Setenv(EnvStrict, "maybe")
+
+	config, formatter, level, err := ConfigFromEnv()
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	for _, key := range []string{EnvLevel, EnvFormat, EnvDepth, EnvStrict} {
+		if !strings.Contains(err.Error(), "logr: "+key+": ") {
+			t.Errorf("error doesn't report %s: %v", key, err)
+		}
+	}
+	if level != LevelInfo || config.DefaultDepth != defaultDepth || config.StrictMode {
+		t.Errorf("malformed settings should keep their defaults, got level %s, depth %d, strict %v",
+			level, config.DefaultDepth, config.StrictMode)
+	}
+	if _, ok := formatter.(*PlainTextFormatter); !ok {
+		t.Errorf("formatter = %T, want *PlainTextFormatter", formatter)
+	}
+}
@@ -0,0 +1,248 @@
+package logr
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// FileSink is an io.Writer that appends to Filename and rotates it once it
+// would grow past MaxSizeBytes. Rotated files are renamed Filename.1,
+// Filename.2 and so on, newest first, and only MaxBackups of them are
+// kept. Add it to a logger with Logger.AddSink and Close it when done:
+//
+//	sink := &logr.FileSink{Filename: "app.log", MaxSizeBytes: 10 << 20, MaxBackups: 5}
+//	defer sink.Close()
+//	logger.AddSink(sink)
+//
+// With Compress set, each rotated file is gzipped in the background to
+// Filename.1.gz. Until then it waits as Filename.rotated-N, and the
+// backups only shift up once it is done, so Write never waits on gzip. A
+// failed compression is reported to ErrorHandler and the uncompressed file
+// is kept, as Filename.1.
+//
+// The file is opened on the first write. FileSink is safe for concurrent
+// use.
+type FileSink struct {
+	Filename     string
+	MaxSizeBytes int64 // Zero disables rotation
+	MaxBackups   int   // Zero keeps no rotated files
+	Compress     bool
+	ErrorHandler ErrorHandler // Nil means print to os.Stderr
+
+	mu      sync.Mutex
+	file    *os.File
+	size    int64
+	rotated int // Files handed to the compressor, numbering their names
+
+	queueMu     sync.Mutex
+	queue       []string // Rotated files waiting to be compressed
+	compressing bool     // compressLoop is running
+	compressed  sync.WaitGroup
+}
+
+func (s *FileSink) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.file == nil {
+		if err := s.open(); err != nil {
+			return 0, err
+		}
+	}
+
+	if s.MaxSizeBytes > 0 && s.size > 0 && s.size+int64(len(p)) > s.MaxSizeBytes {
+		if err := s.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := s.file.Write(p)
+	s.size += int64(n)
+	return n, err
+}
+
+// Close waits for any in-flight compression and closes the current file.
+// A later Write reopens it.
+func (s *FileSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.compressed.Wait()
+
+	if s.file == nil {
+		return nil
+	}
+	err := s.file.Close()
+	s.file = nil
+	return err
+}
+
+// open opens Filename for appending. Callers must hold s.mu.
+func (s *FileSink) open() error {
+	f, err := os.OpenFile(s.Filename, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("logr: open log file: %w", err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("logr: open log file: %w", err)
+	}
+
+	s.file = f
+	s.size = info.Size()
+	return nil
+}
+
+// rotate moves the current file out of the way and opens a fresh one.
+// Without Compress the backups shift up by one and the file becomes
+// Filename.1; with it the file is queued for compressLoop, which shifts
+// them once it is compressed. Callers must hold s.mu.
+func (s *FileSink) rotate() error {
+	if err := s.file.Close(); err != nil {
+		return fmt.Errorf("logr: rotate log file: %w", err)
+	}
+	s.file = nil
+
+	switch {
+	case s.MaxBackups <= 0:
+		if err := os.Remove(s.Filename); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("logr: rotate log file: %w", err)
+		}
+	case s.Compress:
+		s.rotated++
+		staged := fmt.Sprintf("%s.rotated-%d", s.Filename, s.rotated)
+		if err := replaceFile(s.Filename, staged); err != nil {
+			return fmt.Errorf("logr: rotate log file: %w", err)
+		}
+		s.queueCompression(staged)
+	default:
+		if err := s.shiftBackups(s.Filename, s.backupName(1)); err != nil {
+			return fmt.Errorf("logr: rotate log file: %w", err)
+		}
+	}
+
+	return s.open()
+}
+
+// shiftBackups moves every backup up by one, dropping the oldest, then
+// renames src to dst, the newest backup. Only one goroutine shifts
+// backups: the writer without Compress, compressLoop with it.
+func (s *FileSink) shiftBackups(src, dst string) error {
+	// The oldest backup falls off the end, compressed or not
+	for _, name := range []string{s.backupName(s.MaxBackups), s.backupName(s.MaxBackups) + ".gz"} {
+		if err := os.Remove(name); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	for i := s.MaxBackups - 1; i >= 1; i-- {
+		if err := replaceFile(s.backupName(i), s.backupName(i+1)); err != nil {
+			return err
+		}
+		if err := replaceFile(s.backupName(i)+".gz", s.backupName(i+1)+".gz"); err != nil {
+			return err
+		}
+	}
+	return replaceFile(src, dst)
+}
+
+// queueCompression hands a rotated file to compressLoop, starting it
+// unless it is already running.
+func (s *FileSink) queueCompression(name string) {
+	s.queueMu.Lock()
+	defer s.queueMu.Unlock()
+	s.queue = append(s.queue, name)
+	if !s.compressing {
+		s.compressing = true
+		s.compressed.Add(1)
+		go s.compressLoop()
+	}
+}
+
+// compressLoop gzips queued files in rotation order and makes each the
+// newest backup, until the queue is empty.
+func (s *FileSink) compressLoop() {
+	defer s.compressed.Done()
+	for {
+		s.queueMu.Lock()
+		if len(s.queue) == 0 {
+			s.compressing = false
+			s.queueMu.Unlock()
+			return
+		}
+		staged := s.queue[0]
+		s.queue = s.queue[1:]
+		s.queueMu.Unlock()
+
+		src, dst := staged+".gz", s.backupName(1)+".gz"
+		if err := compressFile(staged); err != nil {
+			s.handleError(fmt.Errorf("logr: compress %s: %w", staged, err))
+			src, dst = staged, s.backupName(1)
+		}
+		if err := s.shiftBackups(src, dst); err != nil {
+			s.handleError(fmt.Errorf("logr: rotate log file: %w", err))
+		}
+	}
+}
+
+func (s *FileSink) handleError(err error) {
+	if s.ErrorHandler != nil {
+		s.ErrorHandler(err)
+		return
+	}
+	stderrErrorHandler(err)
+}
+
+func (s *FileSink) backupName(n int) string {
+	return fmt.Sprintf("%s.%d", s.Filename, n)
+}
+
+// replaceFile renames src to dst, removing dst first because rename does
+// not overwrite an existing file on every platform. A missing src is not
+// an error.
+func replaceFile(src, dst string) error {
+	if _, err := os.Stat(src); os.IsNotExist(err) {
+		return nil
+	}
+	if err := os.Remove(dst); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return os.Rename(src, dst)
+}
+
+// compressFile is swapped out in tests to hold up or fail compression.
+var compressFile = gzipFile
+
+// gzipFile compresses name to name.gz and removes name. On failure the
+// partial .gz is removed and name is left in place.
+func gzipFile(name string) (err error) {
+	src, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(name+".gz", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			os.Remove(name + ".gz")
+		}
+	}()
+
+	zw := gzip.NewWriter(dst)
+	_, err = io.Copy(zw, src)
+	err = errors.Join(err, zw.Close(), dst.Close())
+	if err != nil {
+		return err
+	}
+
+	src.Close()
+	return os.Remove(name)
+}
@@ -0,0 +1,279 @@
+package logr
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func readFile(t *testing.T, name string) string {
+	t.Helper()
+	data, err := os.ReadFile(name)
+	if err != nil {
+		t.Fatalf("ReadFile(%s) error = %v", name, err)
+	}
+	return string(data)
+}
+
+func TestFileSinkRotatesBySize(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "app.log")
+	sink := &FileSink{Filename: name, MaxSizeBytes: 10, MaxBackups: 2}
+	defer sink.Close()
+
+	for _, line := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+		if _, err := sink.Write([]byte(line)); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+
+	if got := readFile(t, name); got != "fourth\n" {
+		t.Errorf("current file = %q, want %q", got, "fourth\n")
+	}
+	if got := readFile(t, name+".1"); got != "third\n" {
+		t.Errorf("backup 1 = %q, want %q", got, "third\n")
+	}
+	if got := readFile(t, name+".2"); got != "second\n" {
+		t.Errorf("backup 2 = %q, want %q", got, "second\n")
+	}
+	if _, err := os.Stat(name + ".3"); !os.IsNotExist(err) {
+		t.Errorf("expected only 2 backups, stat .3 error = %v", err)
+	}
+}
+
+func TestFileSinkNoBackups(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "app.log")
+	sink := &FileSink{Filename: name, MaxSizeBytes: 10}
+	defer sink.Close()
+
+	sink.Write([]byte("first line\n"))
+	sink.Write([]byte("second line\n"))
+
+	if got := readFile(t, name); got != "second line\n" {
+		t.Errorf("current file = %q, want %q", got, "second line\n")
+	}
+	if _, err := os.Stat(name + ".1"); !os.IsNotExist(err) {
+		t.Errorf("expected no backups, stat .1 error = %v", err)
+	}
+}
+
+func TestFileSinkAppendsToExistingFile(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "app.log")
+	if err := os.WriteFile(name, []byte("old\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	// A leftover backup must not stop rotation
+	if err := os.WriteFile(name+".1", []byte("stale\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	sink := &FileSink{Filename: name, MaxSizeBytes: 8, MaxBackups: 1}
+	defer sink.Close()
+
+	if _, err := sink.Write([]byte("new line\n")); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	if got := readFile(t, name+".1"); got != "old\n" {
+		t.Errorf("backup 1 = %q, want %q", got, "old\n")
+	}
+	if got := readFile(t, name); got != "new line\n" {
+		t.Errorf("current file = %q, want %q", got, "new line\n")
+	}
+}
+
+func TestFileSinkConcurrentWrites(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "app.log")
+	sink := &FileSink{Filename: name, MaxSizeBytes: 256, MaxBackups: 100}
+	defer sink.Close()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 25; j++ {
+				sink.Write([]byte("concurrent line\n"))
+			}
+		}()
+	}
+	wg.Wait()
+	sink.Close()
+
+	files, _ := filepath.Glob(name + "*")
+	lines := 0
+	for _, f := range files {
+		for _, line := range strings.Split(strings.TrimSpace(readFile(t, f)), "\n") {
+			if line != "concurrent line" {
+				t.Fatalf("%s has a torn line %q", f, line)
+			}
+			lines++
+		}
+	}
+	if lines != 500 {
+		t.Errorf("found %d lines across %d files, want 500", lines, len(files))
+	}
+}
+
+func TestFileSinkAsLoggerSink(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "app.log")
+	sink := &FileSink{Filename: name}
+	defer sink.Close()
+
+	var out bytes.Buffer
+	logger := New(&PlainTextFormatter{}, LevelInfo, DefaultConfig())
+	logger.SetOutput(&out)
+	logger.AddSink(sink)
+
+	logger.Info("to file")
+
+	if got := readFile(t, name); !strings.Contains(got, "to file") {
+		t.Errorf("expected file to contain the line, got %q", got)
+	}
+}
+
+func readGzip(t *testing.T, name string) string {
+	t.Helper()
+	f, err := os.Open(name)
+	if err != nil {
+		t.Fatalf("Open(%s) error = %v", name, err)
+	}
+	defer f.Close()
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatalf("gzip.NewReader(%s) error = %v", name, err)
+	}
+	data, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatalf("reading %s: %v", name, err)
+	}
+	return string(data)
+}
+
+func TestFileSinkCompress(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "app.log")
+	var errs []error
+	sink := &FileSink{
+		Filename:     name,
+		MaxSizeBytes: 10,
+		MaxBackups:   2,
+		Compress:     true,
+		ErrorHandler: func(err error) { errs = append(errs, err) },
+	}
+
+	for _, line := range []string{"first\n", "second\n", "third\n"} {
+		sink.Write([]byte(line))
+	}
+	// Close waits for in-flight compression
+	if err := sink.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	if len(errs) != 0 {
+		t.Fatalf("unexpected compression errors: %v", errs)
+	}
+	if got := readGzip(t, name+".1.gz"); got != "second\n" {
+		t.Errorf("backup 1 = %q, want %q", got, "second\n")
+	}
+	if got := readGzip(t, name+".2.gz"); got != "first\n" {
+		t.Errorf("backup 2 = %q, want %q", got, "first\n")
+	}
+	for _, plain := range []string{name + ".1", name + ".2"} {
+		if _, err := os.Stat(plain); !os.IsNotExist(err) {
+			t.Errorf("expected %s to be replaced by its .gz, stat error = %v", plain, err)
+		}
+	}
+}
+
+func setCompressFile(t *testing.T, fn func(name string) error) {
+	t.Helper()
+	prev := compressFile
+	compressFile = fn
+	t.Cleanup(func() { compressFile = prev })
+}
+
+func TestFileSinkCompressDoesNotBlockWrites(t *testing.T) {
+	release := make(chan struct{})
+	setCompressFile(t, func(name string) error {
+		<-release
+		return gzipFile(name)
+	})
+
+	name := filepath.Join(t.TempDir(), "app.log")
+	sink := &FileSink{Filename: name, MaxSizeBytes: 10, MaxBackups: 3, Compress: true}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for _, line := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+			sink.Write([]byte(line))
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Write waited for compression")
+	}
+
+	close(release)
+	if err := sink.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	for i, want := range []string{"third\n", "second\n", "first\n"} {
+		if got := readGzip(t, fmt.Sprintf("%s.%d.gz", name, i+1)); got != want {
+			t.Errorf("backup %d = %q, want %q", i+1, got, want)
+		}
+	}
+	if got := readFile(t, name); got != "fourth\n" {
+		t.Errorf("current file = %q, want %q", got, "fourth\n")
+	}
+}
+
+func TestFileSinkCompressFailureKeepsBackup(t *testing.T) {
+	setCompressFile(t, func(string) error { return errors.New("disk full") })
+
+	name := filepath.Join(t.TempDir(), "app.log")
+	var errs []error
+	sink := &FileSink{
+		Filename:     name,
+		MaxSizeBytes: 10,
+		MaxBackups:   2,
+		Compress:     true,
+		ErrorHandler: func(err error) { errs = append(errs, err) },
+	}
+	sink.Write([]byte("first\n"))
+	sink.Write([]byte("second\n"))
+	sink.Close()
+
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "disk full") {
+		t.Errorf("errors = %v, want the compression failure", errs)
+	}
+	if got := readFile(t, name+".1"); got != "first\n" {
+		t.Errorf("backup 1 = %q, want it kept uncompressed", got)
+	}
+}
+
+func TestGzipFileFailureKeepsOriginal(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "app.log.1")
+	if err := os.WriteFile(name, []byte("keep me\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	// A directory in the way makes creating the .gz fail
+	if err := os.Mkdir(name+".gz", 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := gzipFile(name); err == nil {
+		t.Fatal("expected gzipFile to fail")
+	}
+	if got := readFile(t, name); got != "keep me\n" {
+		t.Errorf("original = %q, want it kept", got)
+	}
+}
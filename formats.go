@@ -1,60 +1,706 @@
 package logr
 
 import (
+	"bytes"
 	"encoding/json"
+	"encoding/xml"
 	"fmt"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
+	"unicode"
 )
 
 const TimeFormat = time.RFC3339
 
+// Special Config.TimeFormat values that render epoch timestamps instead of
+// a time.Format layout.
+const (
+	TimeFormatUnix   = "unix"   // Seconds since the epoch
+	TimeFormatUnixMs = "unixms" // Milliseconds since the epoch
+)
+
+// Formatter renders a LogEntry as one output line.
+//
+// Format is called concurrently: every goroutine that logs formats its own
+// entry, outside the lock that serializes writes, so a slow formatter
+// doesn't hold up the others. Implementations must be safe for concurrent
+// use. The formatters in this package are, as they keep no state between
+// calls; a custom formatter that does, such as a counter or a reused
+// buffer, must guard it with a mutex or use sync.Pool.
 type Formatter interface {
 	Format(entry LogEntry) string
 }
 
+// TryFormatter is implemented by formatters that can fail, such as
+// JSONFormatter with a metadata value that can't be encoded. The logger
+// prefers TryFormat and reports its error to the error handler instead of
+// writing a broken line.
+type TryFormatter interface {
+	Formatter
+	TryFormat(entry LogEntry) (string, error)
+}
+
+// HeaderEmitter is implemented by formatters whose output starts with a
+// header, such as the column names of CSVFormatter. The logger writes the
+// header once, before its first line, to its outputs and to the writers
+// added with AddSink that it has at that point; entry sinks don't get it.
+// Header returns false when there is nothing to write.
+type HeaderEmitter interface {
+	Formatter
+	Header() (string, bool)
+}
+
 type PlainTextFormatter struct{}
 
+// newlineEscaper writes CR and LF as escape sequences for
+// Config.EscapeNewlines.
+var newlineEscaper = strings.NewReplacer("\r", `\r`, "\n", `\n`)
+
 func (f *PlainTextFormatter) Format(entry LogEntry) string {
-	baseStr := fmt.Sprintf("[%s] [%s] [%v] %s", entry.Level, entry.Layer, entry.Timestamp.Format(TimeFormat), entry.Message)
+	baseStr := fmt.Sprintf("[%s] [%s] [%v]", entry.Level, entry.Layer, entry.FormattedTime())
+	if caller := entry.Caller(); caller != "" {
+		baseStr += " [" + caller + "]"
+	}
+	baseStr += " " + plainMessage(entry)
 
-	if entry.Metadata != nil && len(entry.Metadata.Data) > 0 {
-		var metadataStr []string
-		for key, value := range entry.Metadata.Data {
-			metadataStr = append(metadataStr, fmt.Sprintf("%s=%v", key, value))
+	if fields := plainFields(entry); fields != "" {
+		baseStr = baseStr + " " + fields
+	}
+
+	return baseStr + plainStack(entry)
+}
+
+// plainMessage returns entry's message, escaped per Config.EscapeNewlines.
+func plainMessage(entry LogEntry) string {
+	if entry.escapeNewlines {
+		return newlineEscaper.Replace(entry.Message)
+	}
+	return entry.Message
+}
+
+// plainFields renders entry's error, quoted, followed by its metadata in
+// insertion order as space-separated key=value pairs, or "" when it has
+// neither.
+func plainFields(entry LogEntry) string {
+	var metadataStr []string
+	if entry.Err != nil {
+		metadataStr = append(metadataStr, ErrorKey+"="+strconv.Quote(entry.Err.Error()))
+	}
+	if entry.Metadata == nil || len(entry.Metadata.Data) == 0 {
+		return strings.Join(metadataStr, " ")
+	}
+
+	entry.Metadata.walk("", func(key string, value any) {
+		field := fmt.Sprintf("%s=%v", key, value)
+		if entry.escapeNewlines {
+			field = newlineEscaper.Replace(field)
 		}
-		metadataJoined := strings.Join(metadataStr, " ")
-		baseStr = baseStr + " " + metadataJoined
+		metadataStr = append(metadataStr, field)
+	})
+	return strings.Join(metadataStr, " ")
+}
+
+// plainStack renders entry's stack trace as indented lines following the
+// entry, or "" when it has none.
+func plainStack(entry LogEntry) string {
+	if entry.Stack == "" {
+		return ""
 	}
-	return baseStr
+
+	var sb strings.Builder
+	for _, line := range strings.Split(entry.Stack, "\n") {
+		sb.WriteString("\n    ")
+		sb.WriteString(line)
+	}
+	return sb.String()
+}
+
+// JSONFormatter renders each entry as a single JSON object. The key names
+// can be changed to match an ingestion schema; empty fields use the
+// default shown next to them.
+type JSONFormatter struct {
+	LevelKey    string // "level"
+	LayerKey    string // "layer"
+	MessageKey  string // "message"
+	TimeKey     string // "timestamp"
+	CallerKey   string // "caller"
+	ErrKey      string // "error", for LogEntry.Err
+	MetadataKey string // "metadata"
+	StackKey    string // "stack"
+
+	// FlattenMetadata writes each metadata field as a top-level key instead
+	// of nesting them under MetadataKey. A field whose name collides with
+	// one of the keys above is prefixed with MetadataKey and a dot, e.g.
+	// "metadata.level".
+	FlattenMetadata bool
+
+	// DurationFormat sets how time.Duration metadata values are written.
+	// The zero value, DurationString, writes them as "1.5s". time.Time
+	// values are always written like the entry's timestamp, following
+	// Config.TimeFormat. Both apply inside nested groups too.
+	DurationFormat DurationFormat
 }
 
-type JSONFormatter struct{}
+// DurationFormat is the JSON representation of time.Duration values.
+type DurationFormat int
 
+const (
+	DurationString DurationFormat = iota // "1.5s", as Duration.String (default)
+	DurationMillis                       // 1500, milliseconds as a number, fractional below 1ms
+	DurationNanos                        // 1500000000, the encoding/json default
+)
+
+// Format returns the JSON line for entry, or "" if it can't be encoded.
+// Use TryFormat to get the error.
 func (f JSONFormatter) Format(entry LogEntry) string {
-	jsonLogEntry := struct {
-		Level     string    `json:"level"`
-		Layer     string    `json:"layer"`
-		Message   string    `json:"message"`
-		Timestamp string    `json:"timestamp"`
-		Metadata  *Metadata `json:"metadata,omitempty"`
-	}{
-		Level:     entry.Level.String(),
-		Layer:     entry.Layer.String(),
-		Message:   entry.Message,
-		Timestamp: entry.Timestamp.Format(TimeFormat),
-		Metadata:  nil,
+	formatted, _ := f.TryFormat(entry)
+	return formatted
+}
+
+func (f JSONFormatter) TryFormat(entry LogEntry) (string, error) {
+	var obj jsonObject
+
+	obj.add(orDefault(f.LevelKey, "level"), entry.Level.String())
+	obj.add(orDefault(f.LayerKey, "layer"), entry.Layer.String())
+	obj.add(orDefault(f.MessageKey, "message"), entry.Message)
+
+	timeKey := orDefault(f.TimeKey, "timestamp")
+	obj.add(timeKey, jsonTime(entry.Timestamp, entry.timeFormat))
+
+	if caller := entry.Caller(); caller != "" {
+		obj.add(orDefault(f.CallerKey, "caller"), caller)
+	}
+
+	errKey := orDefault(f.ErrKey, ErrorKey)
+	if entry.Err != nil {
+		obj.add(errKey, entry.Err.Error())
 	}
 
+	metadataKey := orDefault(f.MetadataKey, "metadata")
+	stackKey := orDefault(f.StackKey, "stack")
 	if entry.Metadata != nil && len(entry.Metadata.Data) > 0 {
-		jsonLogEntry.Metadata = entry.Metadata
+		if f.FlattenMetadata {
+			reserved := []string{
+				orDefault(f.LevelKey, "level"),
+				orDefault(f.LayerKey, "layer"),
+				orDefault(f.MessageKey, "message"),
+				timeKey,
+				orDefault(f.CallerKey, "caller"),
+				errKey,
+				metadataKey,
+				stackKey,
+			}
+			for _, key := range entry.Metadata.keys() {
+				name := key
+				if slices.Contains(reserved, key) {
+					name = metadataKey + "." + key
+				}
+				obj.add(name, f.jsonValue(entry.Metadata.Data[key], entry.timeFormat))
+			}
+		} else {
+			var meta jsonObject
+			for _, key := range entry.Metadata.keys() {
+				meta.add(key, f.jsonValue(entry.Metadata.Data[key], entry.timeFormat))
+			}
+			nested, err := meta.bytes()
+			if err != nil {
+				return "", err
+			}
+			obj.add(metadataKey, json.RawMessage(nested))
+		}
+	}
+
+	if entry.Stack != "" {
+		obj.add(stackKey, entry.Stack)
 	}
 
-	jsonEntry, err := json.Marshal(&jsonLogEntry)
+	jsonEntry, err := obj.bytes()
 	if err != nil {
-		fmt.Printf("failed to encode entry: %s", err)
-		return ""
+		return "", err
+	}
+
+	return string(jsonEntry), nil
+}
+
+// jsonTime returns t as written for timeFormat: epoch formats as JSON
+// numbers, anything else as a formatted string.
+func jsonTime(t time.Time, timeFormat string) any {
+	switch timeFormat {
+	case TimeFormatUnix:
+		return t.Unix()
+	case TimeFormatUnixMs:
+		return t.UnixMilli()
+	case "":
+		return t.Format(TimeFormat)
+	default:
+		return t.Format(timeFormat)
 	}
+}
+
+// jsonValue converts durations and times in a metadata value, including
+// inside map[string]any and Metadata.Group groups, to their configured
+// representation. A group becomes an object with its fields in order.
+func (f JSONFormatter) jsonValue(value any, timeFormat string) any {
+	switch v := value.(type) {
+	case time.Duration:
+		switch f.DurationFormat {
+		case DurationMillis:
+			return float64(v) / float64(time.Millisecond)
+		case DurationNanos:
+			return int64(v)
+		default:
+			return v.String()
+		}
+	case time.Time:
+		return jsonTime(v, timeFormat)
+	case map[string]any:
+		converted := make(map[string]any, len(v))
+		for k, nested := range v {
+			converted[k] = f.jsonValue(nested, timeFormat)
+		}
+		return converted
+	case *Metadata:
+		var group jsonObject
+		for _, key := range v.keys() {
+			group.add(key, f.jsonValue(v.Data[key], timeFormat))
+		}
+		encoded, err := group.bytes()
+		if err != nil {
+			// Encoding v itself reports the error
+			return value
+		}
+		return json.RawMessage(encoded)
+	default:
+		return value
+	}
+}
+
+// jsonObject writes a JSON object field by field, keeping keys in the
+// order they are added. The first encoding error is kept and returned by
+// bytes.
+type jsonObject struct {
+	buf bytes.Buffer
+	err error
+}
+
+func (o *jsonObject) add(key string, value any) {
+	if o.err != nil {
+		return
+	}
+
+	k, err := json.Marshal(key)
+	if err != nil {
+		o.err = err
+		return
+	}
+	v, err := json.Marshal(value)
+	if err != nil {
+		o.err = err
+		return
+	}
+
+	if o.buf.Len() == 0 {
+		o.buf.WriteByte('{')
+	} else {
+		o.buf.WriteByte(',')
+	}
+	o.buf.Write(k)
+	o.buf.WriteByte(':')
+	o.buf.Write(v)
+}
+
+func (o *jsonObject) bytes() ([]byte, error) {
+	if o.err != nil {
+		return nil, o.err
+	}
+	if o.buf.Len() == 0 {
+		return []byte("{}"), nil
+	}
+	o.buf.WriteByte('}')
+	return o.buf.Bytes(), nil
+}
+
+func orDefault(value, def string) string {
+	if value == "" {
+		return def
+	}
+	return value
+}
+
+// LogfmtFormatter renders entries as logfmt key=value pairs:
+//
+//	level=INFO layer=HTTP ts=2025-09-30T19:12:02-03:00 msg="request processed" userID=456
+//
+// Metadata fields are written in the order they were added, and fields in
+// a Metadata.Group with dotted keys, http.method=GET.
+type LogfmtFormatter struct{}
+
+func (f LogfmtFormatter) Format(entry LogEntry) string {
+	var sb strings.Builder
+
+	writeLogfmtPair(&sb, "level", entry.Level.String())
+	writeLogfmtPair(&sb, "layer", entry.Layer.String())
+	writeLogfmtPair(&sb, "ts", entry.FormattedTime())
+	writeLogfmtPair(&sb, "msg", entry.Message)
+
+	if caller := entry.Caller(); caller != "" {
+		writeLogfmtPair(&sb, "caller", caller)
+	}
+
+	if entry.Err != nil {
+		writeLogfmtPair(&sb, ErrorKey, entry.errorText())
+	}
+
+	if entry.Metadata != nil && len(entry.Metadata.Data) > 0 {
+		entry.Metadata.walk("", func(key string, value any) {
+			writeLogfmtPair(&sb, key, fmt.Sprint(value))
+		})
+	}
+
+	if entry.Stack != "" {
+		writeLogfmtPair(&sb, "stack", entry.Stack)
+	}
+
+	return sb.String()
+}
+
+func writeLogfmtPair(sb *strings.Builder, key, value string) {
+	if sb.Len() > 0 {
+		sb.WriteByte(' ')
+	}
+	sb.WriteString(key)
+	sb.WriteByte('=')
+	if logfmtNeedsQuotes(value) {
+		sb.WriteString(strconv.Quote(value))
+	} else {
+		sb.WriteString(value)
+	}
+}
 
-	return string(jsonEntry)
+// logfmtNeedsQuotes reports whether value must be quoted to stay a single
+// logfmt token: empty strings, spaces, '=', quotes and control characters.
+func logfmtNeedsQuotes(value string) bool {
+	if value == "" {
+		return true
+	}
+	for _, r := range value {
+		if r == ' ' || r == '=' || r == '"' || r == '\\' || unicode.IsControl(r) || !unicode.IsPrint(r) {
+			return true
+		}
+	}
+	return false
+}
+
+// csvColumns are the columns written by CSVFormatter, in order.
+var csvColumns = []string{"timestamp", "level", "layer", "message", "error", "metadata", "caller", "stack"}
+
+// CSVFormatter renders each entry as one RFC 4180 CSV row with a fixed set
+// of columns, for loading logs into a spreadsheet or pandas:
+//
+//	timestamp,level,layer,message,error,metadata,caller,stack
+//	2025-09-30T19:12:02-03:00,INFO,HTTP,request processed,,"{""userID"":456}",,
+//
+// Metadata is a JSON object in its own column, and error, caller and stack
+// are empty unless recorded. Fields containing commas, quotes or newlines are
+// quoted, so a row may span several lines. The logger writes the header
+// row before the first entry, see HeaderEmitter.
+type CSVFormatter struct{}
+
+// Header returns the header row, without a trailing newline.
+func (f CSVFormatter) Header() (string, bool) {
+	return csvRecord(csvColumns), true
+}
+
+// Format returns the CSV row for entry, or "" if its metadata can't be
+// encoded. Use TryFormat to get the error.
+func (f CSVFormatter) Format(entry LogEntry) string {
+	formatted, _ := f.TryFormat(entry)
+	return formatted
+}
+
+func (f CSVFormatter) TryFormat(entry LogEntry) (string, error) {
+	var metadata string
+	if entry.Metadata != nil && len(entry.Metadata.Data) > 0 {
+		encoded, err := entry.Metadata.MarshalJSON()
+		if err != nil {
+			return "", err
+		}
+		metadata = string(encoded)
+	}
+
+	return csvRecord([]string{
+		entry.FormattedTime(),
+		entry.Level.String(),
+		entry.Layer.String(),
+		entry.Message,
+		entry.errorText(),
+		metadata,
+		entry.Caller(),
+		entry.Stack,
+	}), nil
+}
+
+// csvRecord joins fields into one CSV row, quoting per RFC 4180 the
+// fields that contain a comma, quote, CR or LF, or leading whitespace.
+func csvRecord(fields []string) string {
+	var sb strings.Builder
+	for i, field := range fields {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		if !csvNeedsQuotes(field) {
+			sb.WriteString(field)
+			continue
+		}
+		sb.WriteByte('"')
+		sb.WriteString(strings.ReplaceAll(field, `"`, `""`))
+		sb.WriteByte('"')
+	}
+	return sb.String()
+}
+
+func csvNeedsQuotes(field string) bool {
+	if field == "" {
+		return false
+	}
+	if field[0] == ' ' || field[0] == '\t' {
+		return true
+	}
+	return strings.ContainsAny(field, ",\"\r\n")
+}
+
+// XMLFormatter renders each entry as a single <logEntry> element, for
+// aggregators that only accept XML:
+//
+//	<logEntry><level>INFO</level><layer>HTTP</layer><message>request processed</message><timestamp>2025-09-30T19:12:02-03:00</timestamp><metadata><field name="userID">456</field></metadata></logEntry>
+//
+// <error>, <caller>, <metadata> and <stack> are left out when empty.
+// Metadata values are written with fmt.Sprint, and fields in a
+// Metadata.Group are named with a dot, "http.method". Text is escaped,
+// newlines included, so every entry stays on one line.
+type XMLFormatter struct{}
+
+func (f XMLFormatter) Format(entry LogEntry) string {
+	var sb strings.Builder
+
+	sb.WriteString("<logEntry>")
+	writeXMLElement(&sb, "level", entry.Level.String())
+	writeXMLElement(&sb, "layer", entry.Layer.String())
+	writeXMLElement(&sb, "message", entry.Message)
+	writeXMLElement(&sb, "timestamp", entry.FormattedTime())
+
+	if entry.Err != nil {
+		writeXMLElement(&sb, "error", entry.errorText())
+	}
+
+	if caller := entry.Caller(); caller != "" {
+		writeXMLElement(&sb, "caller", caller)
+	}
+
+	if entry.Metadata != nil && len(entry.Metadata.Data) > 0 {
+		sb.WriteString("<metadata>")
+		entry.Metadata.walk("", func(key string, value any) {
+			sb.WriteString(`<field name="`)
+			writeXMLText(&sb, key)
+			sb.WriteString(`">`)
+			writeXMLText(&sb, fmt.Sprint(value))
+			sb.WriteString("</field>")
+		})
+		sb.WriteString("</metadata>")
+	}
+
+	if entry.Stack != "" {
+		writeXMLElement(&sb, "stack", entry.Stack)
+	}
+
+	sb.WriteString("</logEntry>")
+	return sb.String()
+}
+
+func writeXMLElement(sb *strings.Builder, name, text string) {
+	sb.WriteByte('<')
+	sb.WriteString(name)
+	sb.WriteByte('>')
+	writeXMLText(sb, text)
+	sb.WriteString("</")
+	sb.WriteString(name)
+	sb.WriteByte('>')
+}
+
+// writeXMLText escapes text for use in element content or a quoted
+// attribute. Characters XML can't represent become U+FFFD.
+func writeXMLText(sb *strings.Builder, text string) {
+	// Writing to a strings.Builder never fails
+	_ = xml.EscapeText(sb, []byte(text))
+}
+
+// StackdriverFormatter renders entries as JSON for Google Cloud Logging
+// (formerly Stackdriver), which reads structured logs from stdout:
+//
+//	{"severity":"WARNING","message":"slow query","time":"2025-09-30T22:12:02.5Z","layer":"DB","ms":812}
+//
+// Levels map to Cloud Logging severities: Trace and Debug to DEBUG, Info to
+// INFO, Warn to WARNING, Error to ERROR and Test to DEFAULT. The timestamp
+// is always RFC 3339 with nanoseconds, whatever Config.TimeFormat says.
+// Metadata fields are written at the top level, where Cloud Logging puts
+// them in the entry's jsonPayload; a field named like one of the special
+// keys is prefixed with "metadata.". The caller becomes the entry's source
+// location and the stack trace is written as stack_trace, which Error
+// Reporting picks up.
+type StackdriverFormatter struct{}
+
+// Special keys understood by Cloud Logging, see
+// https://cloud.google.com/logging/docs/structured-logging
+const (
+	stackdriverSourceLocationKey = "logging.googleapis.com/sourceLocation"
+	stackdriverStackKey          = "stack_trace"
+)
+
+var stackdriverReserved = []string{
+	"severity", "message", "time", "layer", ErrorKey,
+	stackdriverSourceLocationKey, stackdriverStackKey,
+}
+
+// StackdriverSeverity returns the Cloud Logging severity name for level.
+func StackdriverSeverity(level Level) string {
+	switch level {
+	case LevelTrace, LevelDebug:
+		return "DEBUG"
+	case LevelInfo:
+		return "INFO"
+	case LevelWarn:
+		return "WARNING"
+	case LevelError:
+		return "ERROR"
+	default:
+		return "DEFAULT"
+	}
+}
+
+// Format returns the JSON line for entry, or "" if it can't be encoded.
+// Use TryFormat to get the error.
+func (f StackdriverFormatter) Format(entry LogEntry) string {
+	formatted, _ := f.TryFormat(entry)
+	return formatted
+}
+
+func (f StackdriverFormatter) TryFormat(entry LogEntry) (string, error) {
+	var obj jsonObject
+
+	obj.add("severity", StackdriverSeverity(entry.Level))
+	obj.add("message", entry.Message)
+	obj.add("time", entry.Timestamp.Format(time.RFC3339Nano))
+	obj.add("layer", entry.Layer.String())
+
+	if entry.File != "" {
+		obj.add(stackdriverSourceLocationKey, map[string]string{
+			"file": entry.File,
+			"line": strconv.Itoa(entry.Line), // Cloud Logging expects a string
+		})
+	}
+
+	if entry.Err != nil {
+		obj.add(ErrorKey, entry.errorText())
+	}
+
+	if entry.Metadata != nil {
+		for _, key := range entry.Metadata.keys() {
+			name := key
+			if slices.Contains(stackdriverReserved, key) {
+				name = "metadata." + key
+			}
+			obj.add(name, entry.Metadata.Data[key])
+		}
+	}
+
+	if entry.Stack != "" {
+		obj.add(stackdriverStackKey, entry.Stack)
+	}
+
+	line, err := obj.bytes()
+	if err != nil {
+		return "", err
+	}
+	return string(line), nil
+}
+
+// ECSVersion is the Elastic Common Schema version ECSFormatter declares in
+// ecs.version.
+const ECSVersion = "1.6.0"
+
+// ECSFormatter renders entries as Elastic Common Schema JSON, as the
+// official ecs-logging libraries do, for Elasticsearch and Kibana:
+//
+//	{"@timestamp":"2025-09-30T22:12:02.000Z","log.level":"warn","message":"slow query","ecs.version":"1.6.0","log.logger":"DB","ms":812}
+//
+// The layer is written as log.logger and the caller as log.origin.file.*.
+// LogEntry.Err, or else the fields set by WithError, map to error.message
+// and error.type, and the stack trace to error.stack_trace. Other
+// metadata fields are written at the top level; one named like a field
+// above is prefixed with "metadata.". @timestamp is always UTC with
+// milliseconds, whatever Config.TimeFormat says.
+type ECSFormatter struct{}
+
+// ecsTimeFormat is the @timestamp layout used by the ecs-logging libraries.
+const ecsTimeFormat = "2006-01-02T15:04:05.000Z07:00"
+
+var ecsReserved = []string{
+	"@timestamp", "log.level", "message", "ecs.version", "log.logger",
+	"log.origin.file.name", "log.origin.file.line",
+	"error.message", "error.type", "error.stack_trace",
+}
+
+// Format returns the JSON line for entry, or "" if it can't be encoded.
+// Use TryFormat to get the error.
+func (f ECSFormatter) Format(entry LogEntry) string {
+	formatted, _ := f.TryFormat(entry)
+	return formatted
+}
+
+func (f ECSFormatter) TryFormat(entry LogEntry) (string, error) {
+	var obj jsonObject
+
+	obj.add("@timestamp", entry.Timestamp.UTC().Format(ecsTimeFormat))
+	obj.add("log.level", strings.ToLower(entry.Level.String()))
+	obj.add("message", entry.Message)
+	obj.add("ecs.version", ECSVersion)
+	obj.add("log.logger", entry.Layer.String())
+
+	if entry.File != "" {
+		obj.add("log.origin.file.name", entry.File)
+		obj.add("log.origin.file.line", entry.Line)
+	}
+
+	if entry.Err != nil {
+		obj.add("error.message", entry.errorText())
+		obj.add("error.type", fmt.Sprintf("%T", entry.Err))
+	}
+
+	if entry.Metadata != nil {
+		for _, key := range entry.Metadata.keys() {
+			value := entry.Metadata.Data[key]
+			switch {
+			case key == ErrorKey && entry.Err == nil:
+				obj.add("error.message", value)
+			case key == ErrorTypeKey && entry.Err == nil:
+				obj.add("error.type", value)
+			case key == ErrorKey || key == ErrorTypeKey || slices.Contains(ecsReserved, key):
+				obj.add("metadata."+key, value)
+			default:
+				obj.add(key, value)
+			}
+		}
+	}
+
+	if entry.Stack != "" {
+		obj.add("error.stack_trace", entry.Stack)
+	}
+
+	line, err := obj.bytes()
+	if err != nil {
+		return "", err
+	}
+	return string(line), nil
 }
@@ -1,7 +1,13 @@
 package logr
 
 import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 )
@@ -29,6 +35,132 @@ func TestPlainTextFormatterWithMetadata(t *testing.T) {
 	}
 }
 
+func TestFormattersConcurrentUse(t *testing.T) {
+	template, err := NewTemplateFormatter("{time} {level} [{layer}] {message} {fields}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	formatters := map[string]Formatter{
+		"plain":       &PlainTextFormatter{},
+		"color":       &ColorFormatter{enabled: true},
+		"json":        JSONFormatter{},
+		"logfmt":      LogfmtFormatter{},
+		"csv":         CSVFormatter{},
+		"xml":         XMLFormatter{},
+		"stackdriver": StackdriverFormatter{},
+		"ecs":         ECSFormatter{},
+		"template":    template,
+	}
+
+	entry := NewEntry(LevelError, LayerHTTP, "request failed")
+	entry.Timestamp = time.Date(2025, 9, 30, 19, 12, 2, 0, time.UTC)
+	entry.Err = errors.New("timeout")
+	entry.AddMetadata("userID", 123)
+	entry.AddMetadata("nested", map[string]any{"a": 1, "b": []int{2, 3}})
+
+	for name, f := range formatters {
+		t.Run(name, func(t *testing.T) {
+			want := f.Format(*entry)
+
+			var wg sync.WaitGroup
+			for range 8 {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for range 50 {
+						if got := f.Format(*entry); got != want {
+							t.Errorf("concurrent Format = %q, want %q", got, want)
+							return
+						}
+					}
+				}()
+			}
+			wg.Wait()
+		})
+	}
+}
+
+// rendezvousFormatter blocks in Format until want calls are in it at once,
+// which only happens if the logger formats concurrently.
+type rendezvousFormatter struct {
+	want    int
+	mu      sync.Mutex
+	inside  int
+	arrived chan struct{}
+}
+
+func (f *rendezvousFormatter) Format(entry LogEntry) string {
+	f.mu.Lock()
+	f.inside++
+	if f.inside == f.want {
+		close(f.arrived)
+	}
+	f.mu.Unlock()
+
+	select {
+	case <-f.arrived:
+	case <-time.After(2 * time.Second):
+	}
+	return entry.Message
+}
+
+func TestLoggerFormatsConcurrently(t *testing.T) {
+	const goroutines = 4
+	f := &rendezvousFormatter{want: goroutines, arrived: make(chan struct{})}
+	logger := New(f, LevelInfo, DefaultConfig())
+	var buf bytes.Buffer
+	logger.SetOutput(&buf)
+
+	var wg sync.WaitGroup
+	for range goroutines {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			logger.Info("hello")
+		}()
+	}
+	wg.Wait()
+
+	select {
+	case <-f.arrived:
+	default:
+		t.Fatal("Format was never running in several goroutines at once")
+	}
+	if got := strings.Count(buf.String(), "hello"); got != goroutines {
+		t.Errorf("wrote %d lines, want %d", got, goroutines)
+	}
+}
+
+func TestFormattersRenderGroups(t *testing.T) {
+	entry := NewEntry(LevelInfo, LayerHTTP, "request")
+	entry.Timestamp = time.Date(2025, 9, 30, 19, 12, 2, 0, time.UTC)
+	entry.AddMetadata("user", "alice")
+	http := entry.Metadata.Group("http")
+	http.Add("method", "GET")
+	http.Add("status", 200)
+	http.Group("timing").Add("took", 12*time.Millisecond)
+
+	tests := []struct {
+		name      string
+		formatter Formatter
+		want      string
+	}{
+		{"plain", &PlainTextFormatter{}, "request user=alice http.method=GET http.status=200 http.timing.took=12ms"},
+		{"logfmt", LogfmtFormatter{}, `msg=request user=alice http.method=GET http.status=200 http.timing.took=12ms`},
+		{"json", JSONFormatter{}, `"metadata":{"user":"alice","http":{"method":"GET","status":200,"timing":{"took":"12ms"}}}`},
+		{"json millis", JSONFormatter{DurationFormat: DurationMillis}, `"timing":{"took":12}`},
+		{"xml", XMLFormatter{}, `<field name="http.method">GET</field><field name="http.status">200</field>`},
+		{"ecs", ECSFormatter{}, `"http":{"method":"GET","status":200,`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.formatter.Format(*entry); !strings.Contains(got, tt.want) {
+				t.Errorf("got %s\nwant it to contain %s", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestJSONFormatter(t *testing.T) {
 	formatter := JSONFormatter{}
 
@@ -55,3 +187,480 @@ func TestJSONFormatter(t *testing.T) {
 		t.Errorf("expected level INFO in JSON output, got: %s", jsonStr)
 	}
 }
+
+func TestFormattersRenderCaller(t *testing.T) {
+	entry := NewEntry(LevelInfo, LayerHTTP, "with caller")
+	entry.File = "main.go"
+	entry.Line = 42
+
+	plain := (&PlainTextFormatter{}).Format(*entry)
+	if !strings.Contains(plain, "[main.go:42] with caller") {
+		t.Errorf("expected caller in plain text output, got %q", plain)
+	}
+
+	jsonStr := JSONFormatter{}.Format(*entry)
+	if !strings.Contains(jsonStr, `"caller":"main.go:42"`) {
+		t.Errorf("expected caller in JSON output, got %s", jsonStr)
+	}
+
+	entry.File = ""
+	if strings.Contains(JSONFormatter{}.Format(*entry), `"caller":`) {
+		t.Error("expected caller to be omitted when not recorded")
+	}
+}
+
+func TestJSONFormatterStack(t *testing.T) {
+	entry := NewEntry(LevelError, LayerDB, "query failed")
+	entry.Stack = "main.main()\n\t/app/main.go:12"
+
+	jsonStr := JSONFormatter{}.Format(*entry)
+	if !strings.Contains(jsonStr, `"stack":"main.main()\n\t/app/main.go:12"`) {
+		t.Errorf("expected stack in JSON output, got %s", jsonStr)
+	}
+}
+
+func TestLogfmtFormatter(t *testing.T) {
+	entry := LogEntry{
+		Level:     LevelInfo,
+		Layer:     LayerHTTP,
+		Message:   "request processed",
+		Timestamp: time.Date(2025, 9, 29, 12, 0, 0, 0, time.UTC),
+	}
+	meta := NewMetadata()
+	meta.Add("userID", 456)
+	meta.Add("path", "/users")
+	meta.Add("agent", `curl "8.0"`)
+	meta.Add("empty", "")
+	entry.Metadata = meta
+
+	got := LogfmtFormatter{}.Format(entry)
+	want := `level=INFO layer=HTTP ts=2025-09-29T12:00:00Z msg="request processed" userID=456 path=/users agent="curl \"8.0\"" empty=""`
+
+	if got != want {
+		t.Errorf("unexpected logfmt output\n got: %s\nwant: %s", got, want)
+	}
+}
+
+func TestLogfmtFormatterEscapesNewlines(t *testing.T) {
+	entry := NewEntry(LevelError, LayerDB, "line one\nline two")
+
+	got := LogfmtFormatter{}.Format(*entry)
+	if !strings.Contains(got, `msg="line one\nline two"`) {
+		t.Errorf("expected escaped newline in msg, got %s", got)
+	}
+	if strings.Contains(got, "\n") {
+		t.Errorf("expected single-line output, got %q", got)
+	}
+}
+
+func TestFormattersMetadataOrderIsDeterministic(t *testing.T) {
+	entry := LogEntry{
+		Level:     LevelInfo,
+		Layer:     LayerHTTP,
+		Message:   "ordered",
+		Timestamp: time.Date(2025, 9, 29, 12, 0, 0, 0, time.UTC),
+	}
+	meta := NewMetadata()
+	for _, key := range []string{"zeta", "alpha", "mid", "beta", "omega"} {
+		meta.Add(key, 1)
+	}
+	entry.Metadata = meta
+
+	plain := (&PlainTextFormatter{}).Format(entry)
+	if !strings.HasSuffix(plain, "ordered zeta=1 alpha=1 mid=1 beta=1 omega=1") {
+		t.Errorf("expected insertion-ordered metadata in plain text, got %q", plain)
+	}
+
+	jsonStr := JSONFormatter{}.Format(entry)
+	if !strings.Contains(jsonStr, `"metadata":{"zeta":1,"alpha":1,"mid":1,"beta":1,"omega":1}`) {
+		t.Errorf("expected insertion-ordered metadata in JSON, got %s", jsonStr)
+	}
+	for i := 0; i < 20; i++ {
+		if again := (JSONFormatter{}).Format(entry); again != jsonStr {
+			t.Fatalf("expected stable JSON output, got %s and %s", jsonStr, again)
+		}
+	}
+}
+
+func TestJSONFormatterCustomFieldNames(t *testing.T) {
+	formatter := JSONFormatter{
+		LevelKey:   "severity",
+		MessageKey: "msg",
+		TimeKey:    "@timestamp",
+	}
+
+	entry := LogEntry{
+		Level:     LevelWarn,
+		Layer:     LayerDB,
+		Message:   "slow query",
+		Timestamp: time.Date(2025, 9, 29, 12, 0, 0, 0, time.UTC),
+	}
+
+	got := formatter.Format(entry)
+	want := `{"severity":"WARN","layer":"DB","msg":"slow query","@timestamp":"2025-09-29T12:00:00Z"}`
+	if got != want {
+		t.Errorf("unexpected JSON output\n got: %s\nwant: %s", got, want)
+	}
+}
+
+func TestJSONFormatterFlattenMetadata(t *testing.T) {
+	entry := LogEntry{
+		Level:     LevelInfo,
+		Layer:     LayerHTTP,
+		Message:   "request",
+		Timestamp: time.Date(2025, 9, 29, 12, 0, 0, 0, time.UTC),
+	}
+	meta := NewMetadata()
+	meta.Add("requestID", "abc")
+	meta.Add("level", "admin")
+	entry.Metadata = meta
+
+	got := JSONFormatter{FlattenMetadata: true}.Format(entry)
+	want := `{"level":"INFO","layer":"HTTP","message":"request","timestamp":"2025-09-29T12:00:00Z","requestID":"abc","metadata.level":"admin"}`
+	if got != want {
+		t.Errorf("unexpected flattened JSON\n got: %s\nwant: %s", got, want)
+	}
+
+	nested := JSONFormatter{}.Format(entry)
+	if !strings.Contains(nested, `"metadata":{`) {
+		t.Errorf("expected nested metadata by default, got %s", nested)
+	}
+}
+
+func TestJSONFormatterMetadataNotDoubleNested(t *testing.T) {
+	entry := NewEntry(LevelInfo, LayerHTTP, "request")
+	entry.AddMetadata("requestID", "abc")
+
+	jsonStr := JSONFormatter{}.Format(*entry)
+	if !strings.Contains(jsonStr, `"metadata":{"requestID":"abc"}`) {
+		t.Errorf("expected metadata fields directly under metadata, got %s", jsonStr)
+	}
+}
+
+func TestJSONFormatterTryFormatError(t *testing.T) {
+	entry := NewEntry(LevelInfo, LayerHTTP, "bad metadata")
+	entry.AddMetadata("ch", make(chan int))
+
+	formatted, err := JSONFormatter{}.TryFormat(*entry)
+	if err == nil {
+		t.Fatal("expected an encoding error")
+	}
+	if formatted != "" {
+		t.Errorf("expected empty output on error, got %q", formatted)
+	}
+	if got := (JSONFormatter{}).Format(*entry); got != "" {
+		t.Errorf("expected Format to return empty string on error, got %q", got)
+	}
+}
+
+func TestCSVFormatter(t *testing.T) {
+	entry := LogEntry{
+		Level:     LevelInfo,
+		Layer:     LayerHTTP,
+		Message:   `said "hi", then left`,
+		Timestamp: time.Date(2025, 9, 29, 12, 0, 0, 0, time.UTC),
+		File:      "user.go",
+		Line:      42,
+	}
+	meta := NewMetadata()
+	meta.Add("userID", 456)
+	entry.Metadata = meta
+
+	f := CSVFormatter{}
+	if got, ok := f.Header(); !ok || got != "timestamp,level,layer,message,error,metadata,caller,stack" {
+		t.Errorf("Header() = %s, %v, want the column names", got, ok)
+	}
+
+	got := f.Format(entry)
+	want := `2025-09-29T12:00:00Z,INFO,HTTP,"said ""hi"", then left",,"{""userID"":456}",user.go:42,`
+	if got != want {
+		t.Errorf("unexpected CSV output\n got: %s\nwant: %s", got, want)
+	}
+}
+
+func TestCSVFormatterRoundTrips(t *testing.T) {
+	entry := NewEntry(LevelError, LayerDB, "line one\nline two")
+	entry.Stack = "main.main()\n\t/app/main.go:12"
+
+	f := CSVFormatter{}
+	header, _ := f.Header()
+	input := header + "\n" + f.Format(*entry) + "\n"
+
+	records, err := csv.NewReader(strings.NewReader(input)).ReadAll()
+	if err != nil {
+		t.Fatalf("ReadAll() error = %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("got %d records, want header and one row", len(records))
+	}
+	row := records[1]
+	if row[3] != "line one\nline two" || row[4] != "" || row[5] != "" || row[7] != entry.Stack {
+		t.Errorf("row = %q, want the message, no error or metadata and the stack", row)
+	}
+}
+
+func TestCSVFormatterTryFormatError(t *testing.T) {
+	entry := NewEntry(LevelInfo, LayerHTTP, "bad")
+	entry.AddMetadata("ch", make(chan int))
+
+	if _, err := (CSVFormatter{}).TryFormat(*entry); err == nil {
+		t.Error("expected an error for unencodable metadata")
+	}
+}
+
+func TestXMLFormatter(t *testing.T) {
+	entry := LogEntry{
+		Level:     LevelInfo,
+		Layer:     LayerHTTP,
+		Message:   "a < b & c",
+		Timestamp: time.Date(2025, 9, 29, 12, 0, 0, 0, time.UTC),
+	}
+	meta := NewMetadata()
+	meta.Add("userID", 456)
+	meta.Add(`q"uote`, "<tag>")
+	entry.Metadata = meta
+
+	got := XMLFormatter{}.Format(entry)
+	want := `<logEntry><level>INFO</level><layer>HTTP</layer><message>a &lt; b &amp; c</message>` +
+		`<timestamp>2025-09-29T12:00:00Z</timestamp><metadata><field name="userID">456</field>` +
+		`<field name="q&#34;uote">&lt;tag&gt;</field></metadata></logEntry>`
+
+	if got != want {
+		t.Errorf("unexpected XML output\n got: %s\nwant: %s", got, want)
+	}
+}
+
+func TestXMLFormatterParses(t *testing.T) {
+	entry := NewEntry(LevelError, LayerDB, "line one\nline two")
+	entry.Stack = "main.main()\n\t/app/main.go:12"
+
+	got := XMLFormatter{}.Format(*entry)
+	if strings.Contains(got, "\n") {
+		t.Errorf("expected single-line output, got %q", got)
+	}
+	if strings.Contains(got, "<metadata>") {
+		t.Errorf("expected no metadata element for nil metadata, got %s", got)
+	}
+
+	var parsed struct {
+		Message string `xml:"message"`
+		Stack   string `xml:"stack"`
+	}
+	if err := xml.Unmarshal([]byte(got), &parsed); err != nil {
+		t.Fatalf("output is not valid XML: %v", err)
+	}
+	if parsed.Message != entry.Message || parsed.Stack != entry.Stack {
+		t.Errorf("parsed = %+v, want the original message and stack", parsed)
+	}
+}
+
+func TestStackdriverFormatter(t *testing.T) {
+	entry := LogEntry{
+		Level:     LevelWarn,
+		Layer:     LayerDB,
+		Message:   "slow query",
+		Timestamp: time.Date(2025, 9, 29, 12, 0, 0, 500, time.UTC),
+		File:      "db.go",
+		Line:      42,
+		Stack:     "main.main()\n\t/app/main.go:12",
+	}
+	meta := NewMetadata()
+	meta.Add("ms", 812)
+	meta.Add("severity", "mine")
+	entry.Metadata = meta
+
+	got := StackdriverFormatter{}.Format(entry)
+	want := `{"severity":"WARNING","message":"slow query","time":"2025-09-29T12:00:00.0000005Z","layer":"DB",` +
+		`"logging.googleapis.com/sourceLocation":{"file":"db.go","line":"42"},` +
+		`"ms":812,"metadata.severity":"mine","stack_trace":"main.main()\n\t/app/main.go:12"}`
+	if got != want {
+		t.Errorf("unexpected output\n got: %s\nwant: %s", got, want)
+	}
+}
+
+func TestStackdriverSeverity(t *testing.T) {
+	tests := map[Level]string{
+		LevelTrace: "DEBUG",
+		LevelDebug: "DEBUG",
+		LevelInfo:  "INFO",
+		LevelWarn:  "WARNING",
+		LevelError: "ERROR",
+		LevelTest:  "DEFAULT",
+	}
+	for level, want := range tests {
+		if got := StackdriverSeverity(level); got != want {
+			t.Errorf("StackdriverSeverity(%s) = %s, want %s", level, got, want)
+		}
+	}
+}
+
+func TestECSFormatter(t *testing.T) {
+	entry := LogEntry{
+		Level:     LevelError,
+		Layer:     LayerDB,
+		Message:   "query failed",
+		Timestamp: time.Date(2025, 9, 29, 9, 0, 0, 123456789, time.FixedZone("BRT", -3*3600)),
+		File:      "db.go",
+		Line:      42,
+		Stack:     "main.main()\n\t/app/main.go:12",
+	}
+	meta := NewMetadata()
+	meta.Add("table", "users")
+	meta.Add(ErrorKey, "timeout")
+	meta.Add(ErrorTypeKey, "*net.OpError")
+	meta.Add("message", "shadowed")
+	entry.Metadata = meta
+
+	got := ECSFormatter{}.Format(entry)
+	want := `{"@timestamp":"2025-09-29T12:00:00.123Z","log.level":"error","message":"query failed",` +
+		`"ecs.version":"1.6.0","log.logger":"DB","log.origin.file.name":"db.go","log.origin.file.line":42,` +
+		`"table":"users","error.message":"timeout","error.type":"*net.OpError","metadata.message":"shadowed",` +
+		`"error.stack_trace":"main.main()\n\t/app/main.go:12"}`
+	if got != want {
+		t.Errorf("unexpected output\n got: %s\nwant: %s", got, want)
+	}
+}
+
+func TestECSFormatterWithLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(ECSFormatter{}, LevelInfo, DefaultConfig())
+	logger.SetOutput(&buf)
+
+	logger.WithField("userID", 5).Info("hello")
+
+	var decoded map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("invalid JSON %q: %v", buf.String(), err)
+	}
+	if decoded["log.level"] != "info" || decoded["ecs.version"] != ECSVersion || decoded["userID"] != float64(5) {
+		t.Errorf("decoded = %v, want ECS fields and userID", decoded)
+	}
+	if _, ok := decoded["@timestamp"]; !ok {
+		t.Errorf("expected @timestamp, got %v", decoded)
+	}
+}
+
+func TestFormattersRenderErr(t *testing.T) {
+	entry := LogEntry{
+		Level:     LevelError,
+		Layer:     LayerDB,
+		Message:   "failed to connect",
+		Timestamp: time.Date(2025, 9, 29, 12, 0, 0, 0, time.UTC),
+		Err:       errors.New("connection refused"),
+	}
+	entry.Metadata = NewMetadata()
+	entry.Metadata.Add("host", "db1")
+
+	tests := []struct {
+		name      string
+		formatter Formatter
+		want      string
+	}{
+		{"plain", &PlainTextFormatter{}, `failed to connect error="connection refused" host=db1`},
+		{"json", JSONFormatter{}, `"message":"failed to connect","timestamp":"2025-09-29T12:00:00Z","error":"connection refused","metadata":{"host":"db1"}`},
+		{"logfmt", LogfmtFormatter{}, `msg="failed to connect" error="connection refused" host=db1`},
+		{"csv", CSVFormatter{}, `failed to connect,connection refused,"{""host"":""db1""}"`},
+		{"xml", XMLFormatter{}, `<error>connection refused</error>`},
+		{"stackdriver", StackdriverFormatter{}, `"layer":"DB","error":"connection refused","host":"db1"`},
+		{"ecs", ECSFormatter{}, `"error.message":"connection refused","error.type":"*errors.errorString","host":"db1"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.formatter.Format(entry); !strings.Contains(got, tt.want) {
+				t.Errorf("Format() = %s, want it to contain %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJSONFormatterDurationAndTimeValues(t *testing.T) {
+	ts := time.Date(2025, 9, 29, 12, 0, 0, 0, time.UTC)
+	entry := LogEntry{Level: LevelInfo, Layer: LayerHTTP, Message: "done", Timestamp: ts}
+	entry.Metadata = NewMetadata()
+	entry.Metadata.Add("took", 1500*time.Millisecond)
+	entry.Metadata.Add("at", ts.Add(time.Hour))
+	entry.Metadata.Add("group", map[string]any{"wait": 250 * time.Microsecond})
+
+	tests := []struct {
+		name       string
+		formatter  JSONFormatter
+		timeFormat string
+		want       string
+	}{
+		{"defaults", JSONFormatter{}, "",
+			`"metadata":{"took":"1.5s","at":"2025-09-29T13:00:00Z","group":{"wait":"250µs"}}`},
+		{"millis", JSONFormatter{DurationFormat: DurationMillis}, "",
+			`"metadata":{"took":1500,"at":"2025-09-29T13:00:00Z","group":{"wait":0.25}}`},
+		{"nanos", JSONFormatter{DurationFormat: DurationNanos}, "",
+			`"metadata":{"took":1500000000,"at":"2025-09-29T13:00:00Z","group":{"wait":250000}}`},
+		{"unix time format", JSONFormatter{}, TimeFormatUnix,
+			`"metadata":{"took":"1.5s","at":1759150800,"group":{"wait":"250µs"}}`},
+		{"custom time format, flattened", JSONFormatter{FlattenMetadata: true}, "15:04",
+			`"timestamp":"12:00","took":"1.5s","at":"13:00","group":{"wait":"250µs"}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := entry
+			e.timeFormat = tt.timeFormat
+			got, err := tt.formatter.TryFormat(e)
+			if err != nil {
+				t.Fatalf("TryFormat() error = %v", err)
+			}
+			if !strings.Contains(got, tt.want) {
+				t.Errorf("TryFormat() = %s, want it to contain %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoggerWritesFormatterHeaderOnce(t *testing.T) {
+	var out, errOut, sink bytes.Buffer
+	logger := New(CSVFormatter{}, LevelInfo, DefaultConfig())
+	logger.SetOutputs(&out, &errOut)
+	logger.AddSink(&sink)
+
+	logger.Info("first")
+	logger.Error("second")
+	logger.Info("third")
+
+	header, _ := CSVFormatter{}.Header()
+	for name, buf := range map[string]*bytes.Buffer{"out": &out, "errOut": &errOut, "sink": &sink} {
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		if lines[0] != header {
+			t.Errorf("%s starts with %q, want the header", name, lines[0])
+		}
+		if strings.Count(buf.String(), header) != 1 {
+			t.Errorf("%s has the header %d times, want once", name, strings.Count(buf.String(), header))
+		}
+	}
+	if got := strings.Count(out.String(), "\n"); got != 3 {
+		t.Errorf("out has %d lines, want the header and two entries", got)
+	}
+}
+
+func TestLoggerHeaderSharedOutput(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(CSVFormatter{}, LevelInfo, DefaultConfig())
+	logger.SetOutput(&buf)
+	logger.AddSink(&buf)
+
+	logger.Info("only")
+
+	header, _ := CSVFormatter{}.Header()
+	if strings.Count(buf.String(), header) != 1 {
+		t.Errorf("expected the header once on a writer used twice, got %q", buf.String())
+	}
+}
+
+func TestLoggerWithoutHeaderEmitter(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(&MockFormatter{}, LevelInfo, DefaultConfig())
+	logger.SetOutput(&buf)
+
+	logger.Info("plain")
+	if buf.String() != "plain\n" {
+		t.Errorf("expected no header, got %q", buf.String())
+	}
+}
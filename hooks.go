@@ -0,0 +1,99 @@
+package logr
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Hook is called with every entry that passes level filtering, e.g. to
+// count errors in a metrics system.
+type Hook func(entry LogEntry)
+
+// Processor can modify an entry before it is formatted, or drop it by
+// returning false. The pointer is only valid during the call; copy *entry
+// to keep it.
+type Processor func(entry *LogEntry) bool
+
+// hooks holds registered hooks and processors in registration order.
+type hooks struct {
+	mu         sync.RWMutex
+	funcs      []Hook
+	processors []Processor
+}
+
+// AddHook registers fn to run for every entry the logger writes. Hooks
+// run in registration order, before the entry is formatted. A panicking
+// hook is recovered and reported to the error handler instead of crashing
+// the caller.
+func (l *Logger) AddHook(fn Hook) {
+	l.hooks.mu.Lock()
+	defer l.hooks.mu.Unlock()
+	l.hooks.funcs = append(l.hooks.funcs, fn)
+	l.retains.Store(true)
+}
+
+// AddProcessor registers fn to run on every entry that passes level
+// filtering, before hooks and formatting. Changes fn makes to the entry
+// are reflected in the output; returning false drops the entry. Processors
+// run in registration order and a panicking processor is recovered,
+// reported to the error handler and treated as keeping the entry.
+func (l *Logger) AddProcessor(fn Processor) {
+	l.hooks.mu.Lock()
+	defer l.hooks.mu.Unlock()
+	l.hooks.processors = append(l.hooks.processors, fn)
+	l.retains.Store(true)
+}
+
+// runProcessors applies every processor to entry and reports whether it
+// should still be written.
+func (l *Logger) runProcessors(entry *LogEntry) bool {
+	l.hooks.mu.RLock()
+	defer l.hooks.mu.RUnlock()
+
+	for i, fn := range l.hooks.processors {
+		if !l.runProcessor(i, fn, entry) {
+			return false
+		}
+	}
+	return true
+}
+
+func (l *Logger) runProcessor(i int, fn Processor, entry *LogEntry) (keep bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			l.reportError(fmt.Errorf("logr: processor %d panicked: %v", i, r))
+			keep = true
+		}
+	}()
+	return fn(entry)
+}
+
+// runHooks calls every registered hook with entry.
+func (l *Logger) runHooks(entry LogEntry) {
+	l.hooks.mu.RLock()
+	defer l.hooks.mu.RUnlock()
+
+	for i, fn := range l.hooks.funcs {
+		l.runHook(i, fn, entry)
+	}
+}
+
+func (l *Logger) runHook(i int, fn Hook, entry LogEntry) {
+	defer func() {
+		if r := recover(); r != nil {
+			l.reportError(fmt.Errorf("logr: hook %d panicked: %v", i, r))
+		}
+	}()
+	fn(entry)
+}
+
+// reportError passes err to the error handler, if one is set.
+func (l *Logger) reportError(err error) {
+	l.mu.Lock()
+	handler := l.errorHandler
+	l.mu.Unlock()
+
+	if handler != nil {
+		handler(err)
+	}
+}
@@ -0,0 +1,106 @@
+package logr
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestAddHookRunsInOrderAfterFiltering(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(&MockFormatter{}, LevelInfo, DefaultConfig())
+	logger.SetOutput(&buf)
+
+	var calls []string
+	logger.AddHook(func(entry LogEntry) {
+		calls = append(calls, "first:"+entry.Message)
+	})
+	logger.AddHook(func(entry LogEntry) {
+		calls = append(calls, "second:"+entry.Level.String())
+	})
+
+	logger.Debug("filtered")
+	logger.Error("boom")
+
+	want := []string{"first:boom", "second:ERROR"}
+	if strings.Join(calls, ",") != strings.Join(want, ",") {
+		t.Errorf("expected hooks %v, got %v", want, calls)
+	}
+}
+
+func TestAddHookPanicIsRecovered(t *testing.T) {
+	var buf bytes.Buffer
+	var reported []error
+	logger := New(&MockFormatter{}, LevelInfo, DefaultConfig())
+	logger.SetOutput(&buf)
+	logger.SetErrorHandler(func(err error) {
+		reported = append(reported, err)
+	})
+
+	ran := false
+	logger.AddHook(func(LogEntry) { panic("bad hook") })
+	logger.AddHook(func(LogEntry) { ran = true })
+
+	logger.Info("still logged")
+
+	if buf.String() != "still logged\n" {
+		t.Errorf("expected line to be written despite panicking hook, got %q", buf.String())
+	}
+	if !ran {
+		t.Error("expected hooks after the panicking one to run")
+	}
+	if len(reported) != 1 || !strings.Contains(reported[0].Error(), "bad hook") {
+		t.Errorf("expected hook panic to be reported, got %v", reported)
+	}
+}
+
+func TestAddProcessorModifiesAndDrops(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(&PlainTextFormatter{}, LevelInfo, DefaultConfig())
+	logger.SetOutput(&buf)
+
+	logger.AddProcessor(func(entry *LogEntry) bool {
+		entry.AddMetadata("hostname", "web-1")
+		return true
+	})
+	logger.AddProcessor(func(entry *LogEntry) bool {
+		return !strings.Contains(entry.Message, "healthcheck")
+	})
+
+	var hooked []string
+	logger.AddHook(func(entry LogEntry) {
+		hooked = append(hooked, entry.Message)
+	})
+
+	logger.Info("GET /healthcheck")
+	logger.WithField("user", "alice").Info("GET /users")
+
+	output := buf.String()
+	if strings.Contains(output, "healthcheck") {
+		t.Errorf("expected healthcheck entry to be dropped, got %q", output)
+	}
+	if !strings.Contains(output, "GET /users user=alice hostname=web-1") {
+		t.Errorf("expected processor field in output, got %q", output)
+	}
+	if len(hooked) != 1 || hooked[0] != "GET /users" {
+		t.Errorf("expected hooks to see only kept entries, got %v", hooked)
+	}
+}
+
+func TestAddProcessorPanicKeepsEntry(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(&MockFormatter{}, LevelInfo, DefaultConfig())
+	logger.SetOutput(&buf)
+	var errs []error
+	logger.SetErrorHandler(func(err error) { errs = append(errs, err) })
+	logger.AddProcessor(func(*LogEntry) bool { panic("bad processor") })
+
+	logger.Info("kept")
+
+	if buf.String() != "kept\n" {
+		t.Errorf("expected entry to be kept, got %q", buf.String())
+	}
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "processor 0 panicked: bad processor") {
+		t.Errorf("expected the panic to be reported, got %v", errs)
+	}
+}
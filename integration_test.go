@@ -141,7 +141,7 @@ func TestEndToEndCaching(t *testing.T) {
 
 	// Check cache was populated
 	logger.registryMu.RLock()
-	cached, exists := logger.layerCache[testPkg]
+	cached, exists := logger.layerCache.peek(testPkg)
 	logger.registryMu.RUnlock()
 
 	if !exists {
@@ -167,8 +167,9 @@ func TestEndToEndMultiplePackages(t *testing.T) {
 	resetLogger()
 
 	logger := InitWithConfig(&PlainTextFormatter{}, LevelInfo, Config{
-		DefaultDepth: 2,
-		SkipSegments: []string{"internal", "pkg"},
+		DefaultDepth:   2,
+		SkipSegments:   []string{"internal", "pkg"},
+		LayerSeparator: "/",
 	})
 
 	packages := map[string]string{
@@ -263,9 +264,10 @@ func TestEndToEndRealWorldScenario(t *testing.T) {
 
 	// App initializes logger once at startup
 	config := Config{
-		DefaultDepth: 2,
-		SkipSegments: []string{"internal", "pkg", "cmd"},
-		StrictMode:   false,
+		DefaultDepth:   2,
+		SkipSegments:   []string{"internal", "pkg", "cmd"},
+		StrictMode:     false,
+		LayerSeparator: "/",
 	}
 
 	logger := InitWithConfig(&PlainTextFormatter{}, LevelInfo, config)
@@ -332,8 +334,9 @@ func TestEndToEndSkipSegmentsFiltering(t *testing.T) {
 	resetLogger()
 
 	config := Config{
-		DefaultDepth: 3,
-		SkipSegments: []string{"internal", "pkg", "adapters"},
+		DefaultDepth:   3,
+		SkipSegments:   []string{"internal", "pkg", "adapters"},
+		LayerSeparator: "/",
 	}
 
 	logger := InitWithConfig(&PlainTextFormatter{}, LevelInfo, config)
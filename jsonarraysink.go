@@ -0,0 +1,78 @@
+package logr
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// JSONArraySink is an io.Writer that turns the newline-delimited JSON
+// written by JSONFormatter into a single JSON array, for tools that read a
+// whole document rather than JSON Lines:
+//
+//	f, _ := os.Create("app.json")
+//	sink := &logr.JSONArraySink{Writer: f}
+//	logger := logr.New(logr.JSONFormatter{}, logr.LevelInfo, logr.DefaultConfig())
+//	logger.SetOutput(sink)
+//	defer f.Close()
+//	defer sink.Close()
+//
+// The opening bracket is written with the first entry, each later entry is
+// preceded by a comma, and Close writes the closing bracket, or "[]" when
+// nothing was logged. Until Close the output is not valid JSON. Each Write
+// must be one complete entry, which is how the logger writes lines. Close
+// leaves Writer open. JSONArraySink is safe for concurrent use.
+type JSONArraySink struct {
+	Writer io.Writer
+
+	mu      sync.Mutex
+	entries int
+	closed  bool
+}
+
+func (s *JSONArraySink) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return 0, fmt.Errorf("logr: write to JSON array sink: %w", os.ErrClosed)
+	}
+
+	entry := bytes.TrimRight(p, "\r\n")
+	if len(entry) == 0 {
+		return len(p), nil
+	}
+
+	sep := ",\n"
+	if s.entries == 0 {
+		sep = "[\n"
+	}
+	buf := make([]byte, 0, len(sep)+len(entry))
+	buf = append(buf, sep...)
+	buf = append(buf, entry...)
+	if _, err := s.Writer.Write(buf); err != nil {
+		return 0, err
+	}
+	s.entries++
+	return len(p), nil
+}
+
+// Close ends the array. Later writes fail.
+func (s *JSONArraySink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+
+	end := "\n]\n"
+	if s.entries == 0 {
+		end = "[]\n"
+	}
+	_, err := io.WriteString(s.Writer, end)
+	return err
+}
@@ -0,0 +1,63 @@
+package logr
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"testing"
+)
+
+func TestJSONArraySinkWritesValidArray(t *testing.T) {
+	var buf bytes.Buffer
+	sink := &JSONArraySink{Writer: &buf}
+
+	logger := New(JSONFormatter{}, LevelInfo, DefaultConfig())
+	logger.SetOutput(sink)
+
+	logger.Info("first")
+	logger.WithField("n", 2).Warn("second")
+	logger.Error("third")
+	if err := sink.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	var entries []map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entries); err != nil {
+		t.Fatalf("output is not a JSON array: %v\n%s", err, buf.String())
+	}
+	if len(entries) != 3 {
+		t.Fatalf("got %d entries, want 3", len(entries))
+	}
+	if entries[0]["message"] != "first" || entries[2]["message"] != "third" {
+		t.Errorf("entries = %v, want them in logging order", entries)
+	}
+}
+
+func TestJSONArraySinkEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	sink := &JSONArraySink{Writer: &buf}
+
+	if err := sink.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if buf.String() != "[]\n" {
+		t.Errorf("output = %q, want an empty array", buf.String())
+	}
+}
+
+func TestJSONArraySinkCloseIsIdempotent(t *testing.T) {
+	var buf bytes.Buffer
+	sink := &JSONArraySink{Writer: &buf}
+
+	sink.Write([]byte(`{"a":1}` + "\n"))
+	sink.Close()
+	sink.Close()
+
+	if buf.String() != "[\n{\"a\":1}\n]\n" {
+		t.Errorf("output = %q, want one closing bracket", buf.String())
+	}
+	if _, err := sink.Write([]byte("{}\n")); !errors.Is(err, os.ErrClosed) {
+		t.Errorf("Write() after Close error = %v, want os.ErrClosed", err)
+	}
+}
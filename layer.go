@@ -1,6 +1,9 @@
 package logr
 
-import "strings"
+import (
+	"strings"
+	"unicode"
+)
 
 type Layer string
 
@@ -8,12 +11,75 @@ const (
 	LayerHTTP Layer = "HTTP"
 	LayerDB   Layer = "DB"
 	LayerCORE Layer = "CORE"
+
+	// LayerUnknown is used when no layer can be resolved and
+	// Config.DefaultLayer is unset.
+	LayerUnknown Layer = "UNKNOWN"
+)
+
+// LayerCase controls the casing of layers derived from package paths.
+// Layers set explicitly, e.g. with SetLayerForPackage, are used as given.
+type LayerCase int
+
+const (
+	LayerCaseUpper    LayerCase = iota // "paymentGateway" → "PAYMENTGATEWAY" (default)
+	LayerCaseLower                     // "paymentGateway" → "paymentgateway"
+	LayerCaseOriginal                  // "paymentGateway" → "paymentGateway"
 )
 
+// StrictModeAction is what happens in StrictMode when a layer that isn't
+// allowed is assigned, with SetLayer, SetLayerForPackage, WithLayer or a
+// LogEntry passed to Log. Layers derived from package paths aren't
+// checked.
+type StrictModeAction int
+
+const (
+	// StrictPanic panics in SetLayer, SetLayerForPackage and log calls;
+	// SetLayerSafe and SetLayerForPackageSafe return an error wrapping
+	// ErrUnknownLayer instead. This is the default.
+	StrictPanic StrictModeAction = iota
+
+	// StrictError rejects the layer without panicking: the Safe variants
+	// return the error, and the other setters and log calls pass it to the
+	// ErrorHandler. The setting is left unchanged and the entry dropped.
+	StrictError
+
+	// StrictWarn logs a warning, once per layer, and carries on with
+	// Config.DefaultLayer (UNKNOWN unless set) in its place.
+	StrictWarn
+
+	// StrictDrop accepts the assignment but drops every entry written
+	// under the layer, until it is allowed with RegisterLayer.
+	StrictDrop
+)
+
+// write writes s to b in case c, rune by rune like strings.ToUpper and
+// strings.ToLower, without building an intermediate string.
+func (c LayerCase) write(b *strings.Builder, s string) {
+	switch c {
+	case LayerCaseOriginal:
+		b.WriteString(s)
+	case LayerCaseLower:
+		for _, r := range s {
+			b.WriteRune(unicode.ToLower(r))
+		}
+	default:
+		for _, r := range s {
+			if 'a' <= r && r <= 'z' {
+				b.WriteByte(byte(r) - 'a' + 'A')
+				continue
+			}
+			b.WriteRune(unicode.ToUpper(r))
+		}
+	}
+}
+
 func (l Layer) String() string {
 	return string(l)
 }
 
+// RegisterLayer returns the Layer for name, upper-cased. It only builds the
+// name; use Logger.RegisterLayer to also allow the layer in SetLayer.
 func RegisterLayer(name string) Layer {
 	return Layer(strings.ToUpper(name))
 }
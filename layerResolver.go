@@ -1,9 +1,13 @@
 package logr
 
 import (
+	"fmt"
+	"io"
+	"os"
 	"runtime"
-	"slices"
+	"strconv"
 	"strings"
+	"sync"
 )
 
 // ResolveLayer Core resolution functions
@@ -15,111 +19,295 @@ func resolveLayer(logger *Logger, packagePath string) string {
 
 	inheritadLayer := findInheritedLayer(logger, packagePath)
 	if inheritadLayer != nil {
+		if logger.config.ResolutionDebug {
+			debugResolution(packagePath, "inherited=yes", *inheritadLayer)
+		}
 		logger.setCachedLayer(packagePath, *inheritadLayer)
 		return *inheritadLayer
 	}
 
 	logger.registryMu.RLock()
 	depthValue := logger.config.DefaultDepth
+	depthSource := "default"
 
 	if logger.registry[packagePath] != nil && logger.registry[packagePath].explicitDepth != nil {
 		depthValue = *logger.registry[packagePath].explicitDepth
+		depthSource = "explicit"
 	}
 
 	logger.registryMu.RUnlock()
 
-	result := extractFromDepth(packagePath, depthValue, logger.config.SkipSegments)
+	result := extractLayer(packagePath, depthValue, &logger.config)
+	if packagePath == unknownPackage {
+		// The caller couldn't be identified
+		result = logger.config.fallbackLayer()
+	}
 
+	if logger.config.ResolutionDebug {
+		debugResolution(packagePath, fmt.Sprintf("depth=%d (%s) inherited=no", depthValue, depthSource), result)
+	}
 	logger.setCachedLayer(packagePath, result)
 
 	return result
 }
 
+// resolutionDebugOut receives Config.ResolutionDebug output; tests swap it
+// out.
+var resolutionDebugOut io.Writer = os.Stderr
+
+// debugResolution writes one uncached resolution of packagePath for
+// Config.ResolutionDebug, e.g.
+//
+//	logr: resolve package="github.com/myapp/internal/api/handlers" depth=2 (default) inherited=no layer="API/HANDLERS"
+func debugResolution(packagePath, how, layer string) {
+	fmt.Fprintf(resolutionDebugOut, "logr: resolve package=%q %s layer=%q\n", packagePath, how, layer)
+}
+
+// unknownPackage stands in for the package path when the caller can't be
+// identified.
+const unknownPackage = "unknown"
+
+// logrPackage is this package's import path, used to tell logr's own
+// frames apart from the code that called into it.
+var logrPackage = func() string {
+	pc, _, _, _ := runtime.Caller(0)
+	return packageFromFunc(runtime.FuncForPC(pc).Name())
+}()
+
+// isInternalFrame reports whether frame belongs to the logging machinery
+// rather than to the code doing the logging: logr itself (but not its
+// tests) and log/slog when logging through NewSlogHandler.
+func isInternalFrame(frame runtime.Frame) bool {
+	return isInternal(packageFromFunc(frame.Function), frame.File)
+}
+
+func isInternal(pkg, file string) bool {
+	if pkg == logrPackage && !strings.HasSuffix(file, "_test.go") {
+		return true
+	}
+	return pkg == "log/slog"
+}
+
+// callerFrame is the symbolized form of one program counter.
+type callerFrame struct {
+	pkg  string
+	file string
+	line int
+}
+
+// frameCache maps program counters to their callerFrame. Call sites are
+// fixed, so after the first log from a line its stack is walked without
+// symbolizing anything.
+var frameCache sync.Map
+
+func lookupFrame(pc uintptr) callerFrame {
+	if cached, ok := frameCache.Load(pc); ok {
+		return cached.(callerFrame)
+	}
+
+	frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+	cf := callerFrame{pkg: unknownPackage, file: frame.File, line: frame.Line}
+	if frame.Function != "" {
+		cf.pkg = packageFromFunc(frame.Function)
+	}
+	frameCache.Store(pc, cf)
+	return cf
+}
+
 func getCurrentPackage(skip int) string {
 	// Get program counter of caller
-	// skip: how many stack frames to skip
+	// skip: how many stack frames to skip before looking
 	//   0 = GetCurrentPackage itself
 	//   1 = function that called GetCurrentPackage
 	//   2 = function that called that function, etc.
+	// logr's own frames are then skipped too, see getCaller
+	packagePath, _, _ := getCaller(skip + 1)
+	return packagePath
+}
 
-	pc, _, _, ok := runtime.Caller(skip)
-	if !ok {
-		return "unknown" // Couldn't get caller
+// getCaller is like getCurrentPackage but also reports the file and line
+// of the frame, so a single stack walk serves both layer resolution and
+// caller reporting. It starts skip frames above getCaller itself and moves
+// past logr's own frames, so it finds the user's call site no matter how
+// many logr functions sit in between.
+func getCaller(skip int) (packagePath string, file string, line int) {
+	var pcs [32]uintptr
+	// +1 because runtime.Callers counts itself as frame 0
+	n := runtime.Callers(skip+1, pcs[:])
+	if n == 0 {
+		return unknownPackage, "", 0 // Couldn't get caller
 	}
 
-	// Get function info from program counter
-	fn := runtime.FuncForPC(pc)
-	if fn == nil {
-		return "unknown"
+	// runtime.Callers lists inlined calls as frames of their own, so each
+	// pc maps to exactly one frame
+	var frame callerFrame
+	for _, pc := range pcs[:n] {
+		frame = lookupFrame(pc)
+		if !isInternal(frame.pkg, frame.file) {
+			break
+		}
 	}
+	return frame.pkg, frame.file, frame.line
+}
 
+// callerFromPC resolves a program counter captured elsewhere, such as
+// slog.Record.PC, to the same values getCaller reports.
+func callerFromPC(pc uintptr) (packagePath string, file string, line int) {
+	if pc == 0 {
+		return unknownPackage, "", 0
+	}
+	frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+	if frame.Function == "" {
+		return unknownPackage, frame.File, frame.Line
+	}
+	return packageFromFunc(frame.Function), frame.File, frame.Line
+}
+
+// packageFromFunc extracts the package path from a fully qualified
+// function name.
+func packageFromFunc(fullName string) string {
 	// Function name format: "github.com/user/pkg/subpkg.FuncName"
 	// or with receiver: "github.com/user/pkg.(*Type).Method"
-	fullName := fn.Name()
+	// or a closure: "github.com/user/pkg.FuncName.func1"
 
-	// fmt.Printf("DEBUG: fullName = %s, skip = %d\n", fullName, skip)
-	// Extract package path (everything before last dot)
-	// "github.com/user/pkg.FuncName" -> "github.com/user/pkg"
-	lastDot := strings.LastIndex(fullName, ".")
-	if lastDot == -1 {
-		return "unknown"
+	// Drop type arguments, which may contain slashes and dots of their own:
+	// "pkg.Map[...]" -> "pkg.Map"
+	if idx := strings.Index(fullName, "["); idx != -1 {
+		fullName = fullName[:idx]
 	}
 
-	packagePath := fullName[:lastDot]
-
-	// Clean up method receivers: "pkg.(*Type)" -> "pkg"
-	if idx := strings.Index(packagePath, ".("); idx != -1 {
-		packagePath = packagePath[:idx]
+	// The package path ends at the first dot after the last slash
+	// "github.com/user/pkg.FuncName.func1" -> "github.com/user/pkg"
+	lastSlash := strings.LastIndex(fullName, "/")
+	dot := strings.Index(fullName[lastSlash+1:], ".")
+	if dot == -1 {
+		return unknownPackage
 	}
 
+	packagePath := fullName[:lastSlash+1+dot]
+
+	// Dots in the last path element are escaped in symbol names:
+	// "gopkg.in/yaml%2ev3.Marshal" -> "gopkg.in/yaml.v3"
+	if strings.Contains(packagePath, "%2e") {
+		packagePath = strings.ReplaceAll(packagePath, "%2e", ".")
+	}
 	return packagePath
 }
 
-// Finding the right skip value:
-// GetCurrentPackage(3) typical for:
+// slashPath normalizes a source file path to forward slashes, so callers
+// and stack traces read the same whichever OS produced them. Go import
+// paths, which the layer is built from, always use forward slashes.
+func slashPath(file string) string {
+	return strings.ReplaceAll(file, `\`, "/")
+}
+
+// captureStack returns a symbolized stack trace starting at the user's
+// frame, the first one getCaller would report, in the same shape as a Go
+// panic trace:
 //
-//	[0] runtime.Caller
-//	[1] GetCurrentPackage
-//	[2] resolveLayer or Log
-//	[3] Info/Error/Debug <- actual caller we want
+//	main.main()
+//		/path/to/main.go:12
+func captureStack() string {
+	// Skip runtime.Callers, stackFrom and captureStack
+	return stackFrom(3, isInternalFrame)
+}
+
+// stackFrom formats the stack skip frames above runtime.Callers, leaving
+// out the leading frames for which internal reports true.
+func stackFrom(skip int, internal func(runtime.Frame) bool) string {
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(skip, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+
+	var sb strings.Builder
+	user := false
+	for {
+		frame, more := frames.Next()
+		if frame.Function == "" {
+			break
+		}
+		if !user && more && internal(frame) {
+			continue
+		}
+		if user {
+			sb.WriteString("\n")
+		}
+		user = true
+		sb.WriteString(frame.Function)
+		sb.WriteString("()\n\t")
+		sb.WriteString(slashPath(frame.File))
+		sb.WriteString(":")
+		sb.WriteString(strconv.Itoa(frame.Line))
+		if !more {
+			break
+		}
+	}
+	return sb.String()
+}
+
+// extractFromDepth returns the layer for packagePath built from its last
+// depth segments, leaving out skipSegments.
 func extractFromDepth(packagePath string, depth int, skipSegments []string) string {
-	// Split path: "a/b/c/d" -> ["a", "b", "c", "d"]
-	segments := strings.Split(packagePath, "/")
+	return extractLayer(packagePath, depth, &Config{SkipSegments: skipSegments, LayerSeparator: "/"})
+}
+
+// extractLayer is extractFromDepth driven by config: ModulePrefix is
+// stripped first, segments listed in SkipSegments are dropped, the rest are
+// joined with LayerSeparator and the result is cased per LayerCase.
+func extractLayer(packagePath string, depth int, config *Config) string {
+	// Drop the module prefix so depth counts from the project root
+	packagePath = config.trimModulePrefix(packagePath)
 
 	// Safety: ensure depth is valid
-	if depth < 0 {
-		depth = 0
-	}
-	if depth > len(segments) {
-		depth = len(segments)
+	if depth <= 0 || packagePath == "" {
+		return config.fallbackLayer()
 	}
 
-	// Take LAST N segments
-	// Example: ["github.com", "myapp", "internal", "db", "postgres"]
-	//          depth=2 → take last 2 → ["db", "postgres"]
-	startIndex := len(segments) - depth
-	if startIndex < 0 {
-		startIndex = 0
+	// Take LAST N segments by scanning back for the Nth slash, so the
+	// path is never split into a slice
+	// Example: "github.com/myapp/internal/db/postgres"
+	//          depth=2 → "db/postgres"
+	startIndex := 0
+	for i, slashes := len(packagePath)-1, 0; i >= 0; i-- {
+		if packagePath[i] == '/' {
+			slashes++
+			if slashes == depth {
+				startIndex = i + 1
+				break
+			}
+		}
 	}
-	relevant := segments[startIndex:]
-
-	// Filter out skipped segments
-	filtered := []string{}
-	for _, seg := range relevant {
-		if !slices.Contains(skipSegments, seg) {
-			filtered = append(filtered, seg)
+	relevant := packagePath[startIndex:]
+
+	// Write the segments that aren't skipped, joined with the separator
+	// and cased as configured
+	// "db/postgres" → "DB/POSTGRES"
+	separator := config.LayerSeparator
+	var b strings.Builder
+	b.Grow(len(relevant) + depth*len(separator))
+
+	written := 0
+	for rest := relevant; ; {
+		seg, after, more := strings.Cut(rest, "/")
+		if !config.ShouldSkipSegment(seg) {
+			if written > 0 {
+				b.WriteString(separator)
+			}
+			config.LayerCase.write(&b, seg)
+			written++
+		}
+		if !more {
+			break
 		}
+		rest = after
 	}
 
 	// Handler empty result
-	if len(filtered) == 0 {
-		return "UNKNOWN"
+	if written == 0 {
+		return config.fallbackLayer()
 	}
 
-	// Join segments and conver to uppercase
-	// ["db", "postgres"] → "db/postgres" → "DB/POSTGRES"
-	result := strings.Join(filtered, "/")
-	return strings.ToUpper(result)
+	return b.String()
 }
 
 func findInheritedLayer(logger *Logger, packagePath string) *string {
@@ -150,20 +338,21 @@ func parentPath(path string) string {
 
 // GetCachedLayer optional caching
 func (l *Logger) getCachedLayer(pkgPath string) (string, bool) {
-	l.registryMu.Lock()
-	defer l.registryMu.Unlock()
+	l.registryMu.RLock()
+	defer l.registryMu.RUnlock()
 
-	cachedValue, ok := l.layerCache[pkgPath]
+	layer, ok := l.layerCache.get(pkgPath)
 	if ok {
-		return cachedValue, true
+		l.layerCache.hits.Add(1)
+	} else {
+		l.layerCache.misses.Add(1)
 	}
-
-	return "", false
+	return layer, ok
 }
 
 func (l *Logger) setCachedLayer(pkgPath string, layer string) {
 	l.registryMu.Lock()
 	defer l.registryMu.Unlock()
 
-	l.layerCache[pkgPath] = layer
+	l.layerCache.set(pkgPath, layer)
 }
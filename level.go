@@ -1,17 +1,33 @@
 package logr
 
+import (
+	"fmt"
+	"strings"
+)
+
 type Level int
 
+// LevelTrace sits below Debug for very verbose output such as function
+// entry and exit. It is negative so the values of the other levels don't
+// change.
+const LevelTrace Level = -1
+
 const (
-	LevelDebug Level = iota // 0 - most verbose
+	LevelDebug Level = iota // 0
 	LevelInfo               // 1
 	LevelWarn               // 2
 	LevelError              // 3
-	LevelTest               // 4 - special test level
+
+	// LevelTest marks lines written with Logger.Test. It is a separate
+	// channel rather than a severity: Test lines are only written when
+	// Config.TestMode is set, whatever the logger's level.
+	LevelTest // 4
 )
 
 func (l Level) String() string {
 	switch l {
+	case LevelTrace:
+		return "TRACE"
 	case LevelDebug:
 		return "DEBUG"
 	case LevelInfo:
@@ -26,3 +42,42 @@ func (l Level) String() string {
 		return "UNKNOWN"
 	}
 }
+
+// threshold returns l for use as a minimum severity. LevelTest is not part
+// of the severity order, so as a threshold it behaves like LevelError.
+func (l Level) threshold() Level {
+	if l > LevelError {
+		return LevelError
+	}
+	return l
+}
+
+// atLeast reports whether l is min or more severe. LevelTest is not a
+// severity, so Test lines are never treated as warnings or errors: they
+// go to the normal output, are sampled and rate limited like Info, and
+// never get a stack trace.
+func (l Level) atLeast(min Level) bool {
+	return l != LevelTest && l >= min
+}
+
+// ParseLevel converts a level name such as "info" or " ERROR " into a Level.
+// Matching is case-insensitive and ignores surrounding whitespace, so it
+// round-trips with Level.String().
+func ParseLevel(s string) (Level, error) {
+	switch strings.ToUpper(strings.TrimSpace(s)) {
+	case "TRACE":
+		return LevelTrace, nil
+	case "DEBUG":
+		return LevelDebug, nil
+	case "INFO":
+		return LevelInfo, nil
+	case "WARN":
+		return LevelWarn, nil
+	case "ERROR":
+		return LevelError, nil
+	case "TEST":
+		return LevelTest, nil
+	default:
+		return LevelInfo, fmt.Errorf("unknown log level %q: expected one of TRACE, DEBUG, INFO, WARN, ERROR, TEST", s)
+	}
+}
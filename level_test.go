@@ -1,6 +1,7 @@
 package logr
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -9,6 +10,7 @@ func TestLevelString(t *testing.T) {
 		level    Level
 		expected string
 	}{
+		{LevelTrace, "TRACE"},
 		{LevelDebug, "DEBUG"}, // Changed order
 		{LevelInfo, "INFO"},
 		{LevelWarn, "WARN"},
@@ -25,6 +27,9 @@ func TestLevelString(t *testing.T) {
 }
 
 func TestLevelOrdering(t *testing.T) {
+	if LevelTrace >= LevelDebug {
+		t.Error("Expected Trace < Debug")
+	}
 	if LevelDebug >= LevelInfo {
 		t.Error("Expected Debug < Info")
 	}
@@ -35,5 +40,52 @@ func TestLevelOrdering(t *testing.T) {
 		t.Error("Expected Warn < Error")
 	}
 
-	t.Log("Level ordering: Debug(0) < Info(1) < Warn(2) < Error(3)")
+	if LevelTest <= LevelTrace {
+		t.Error("Expected Test to stay above Trace")
+	}
+
+	t.Log("Level ordering: Trace(-1) < Debug(0) < Info(1) < Warn(2) < Error(3)")
+}
+
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected Level
+	}{
+		{"trace", LevelTrace},
+		{"debug", LevelDebug},
+		{"INFO", LevelInfo},
+		{" Warn ", LevelWarn},
+		{"error\n", LevelError},
+		{"test", LevelTest},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseLevel(tt.input)
+		if err != nil {
+			t.Errorf("ParseLevel(%q) returned error: %v", tt.input, err)
+		}
+		if got != tt.expected {
+			t.Errorf("ParseLevel(%q) = %s, want %s", tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestParseLevelRoundTrip(t *testing.T) {
+	for _, level := range []Level{LevelTrace, LevelDebug, LevelInfo, LevelWarn, LevelError, LevelTest} {
+		got, err := ParseLevel(level.String())
+		if err != nil || got != level {
+			t.Errorf("ParseLevel(%q) = %s, %v; want %s", level.String(), got, err, level)
+		}
+	}
+}
+
+func TestParseLevelUnknown(t *testing.T) {
+	_, err := ParseLevel("verbose")
+	if err == nil {
+		t.Fatal("Expected error for unknown level")
+	}
+	if !strings.Contains(err.Error(), "verbose") {
+		t.Errorf("Expected error to mention input, got %q", err.Error())
+	}
 }
@@ -1,148 +1,1060 @@
 package logr
 
 import (
+	"cmp"
+	"errors"
 	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path"
+	"slices"
 	"sync"
+	"sync/atomic"
 )
 
-var once sync.Once
-
-const (
-	skipForSetMethods = 3 // SetLayerForPackage/SetDepth → user code
-	skipForLogging    = 4 // Info/Error/etc → log → getOrResolveLayer → getCurrentPackage → user
+var (
+	once     sync.Once
+	globalMu sync.Mutex // Guards defaultLogger and once against Reset
 )
 
 type Logger struct {
 	formatter     Formatter
 	level         Level
+	layerLevels   map[Layer]Level // Per-layer overrides of level
+	onlyLayers    map[Layer]bool  // Set by OnlyLayers; nil lets every layer through
+	exceptLayers  map[Layer]bool  // Set by ExceptLayers
+	sampler       *sampler        // Set by SampleEvery
+	limiter       *rateLimiter    // Per-layer limits set by SetRateLimit
 	defaultLayer  Layer
 	allowedLayers map[Layer]int
+	// rejectedLayers holds the layers StrictMode turned down with
+	// StrictWarn (warned about) or StrictDrop (dropped)
+	rejectedLayers map[Layer]bool
+	hasRejected    atomic.Bool // rejectedLayers isn't empty
 
 	config     Config
+	redactor   *redactor
 	registry   map[string]*packageConfig
-	layerCache map[string]string
+	layerCache *layerCache
 	registryMu sync.RWMutex
 
+	out    io.Writer // Trace, Debug, Info and Test
+	errOut io.Writer // Warn and above
+	sinks  []Sink    // Extra destinations that receive every level
+
+	headerDone bool // Set once the HeaderEmitter header has been handed out
+
+	errorHandler ErrorHandler
+	hooks        hooks
+	extractors   []ContextExtractor
+	metrics      metrics // Entries written, see Metrics
+
+	async *asyncWriter // Set when Config.Async is on
+	// Config.DedupConsecutive state for out, errOut (the same one when they
+	// are the same writer) and each of sinks
+	outDedup    *deduper
+	errOutDedup *deduper
+	sinkDedup   []*deduper
+
+	closed atomic.Bool
+	// retains is set once a hook, processor or entry sink is added, any of
+	// which may keep an entry's metadata past the log call
+	retains atomic.Bool
+	nop     bool // Set by Nop; never changes afterwards
+
 	mu sync.Mutex
+	// writeMu serializes synchronous writes; it is taken before mu
+	writeMu sync.Mutex
 }
 
 var defaultLogger *Logger
 
+// fallbackLogger is handed out by GetOrDefault until Init runs.
+var fallbackLogger *Logger
+
+var (
+	// ErrUnknownLayer is returned by SetLayerSafe, and by
+	// SetLayerForPackageSafe in StrictMode, for a layer that hasn't been
+	// allowed or registered. With StrictError it is also passed to the
+	// ErrorHandler.
+	ErrUnknownLayer = errors.New("logr: unknown layer")
+
+	// ErrInvalidDepth is returned by SetDepthSafe for a negative depth.
+	ErrInvalidDepth = errors.New("logr: invalid depth")
+)
+
+// ErrorHandler is called when formatting an entry or writing a log line
+// to an output or sink fails.
+type ErrorHandler func(err error)
+
+// stderrErrorHandler is the default ErrorHandler.
+func stderrErrorHandler(err error) {
+	fmt.Fprintln(os.Stderr, err)
+}
+
+// Init builds the global logger with DefaultConfig. allowedLayers lists the
+// layers SetLayer accepts, each with its priority (see LayerPriority); nil
+// allows none until RegisterLayer is called. Later calls return the logger
+// from the first one.
 func Init(formatter Formatter, level Level, allowedLayers map[Layer]int) *Logger {
-	once.Do(func() {
-		defaultLogger = &Logger{
-			formatter:     formatter,
-			level:         level,
-			allowedLayers: allowedLayers,
+	globalMu.Lock()
+	defer globalMu.Unlock()
 
-			config:     DefaultConfig(),
-			registry:   make(map[string]*packageConfig),
-			layerCache: make(map[string]string),
-		}
+	once.Do(func() {
+		defaultLogger = newLogger(formatter, level, validDefaultConfig())
+		defaultLogger.allowedLayers = maps.Clone(allowedLayers)
 	})
 	return defaultLogger
 }
 
+// New returns an independent Logger that does not touch the global one
+// used by Init and Get. Use it when a process needs several loggers with
+// different formatters or levels, e.g. separate audit and application logs.
+// It panics if config is invalid, like InitWithConfig.
+func New(formatter Formatter, level Level, config Config) *Logger {
+	if err := config.Validate(); err != nil {
+		panic(fmt.Sprintf("Invalid config: %v", err))
+	}
+	return newLogger(formatter, level, config)
+}
+
+// Nop returns a Logger that discards everything, the logging equivalent of
+// io.Discard. All methods work as usual, but no entry is ever built or
+// written, not even after SetLevel or SetOutput. Use it to silence logging
+// in libraries and in tests of downstream packages.
+func Nop() *Logger {
+	l := newLogger(&PlainTextFormatter{}, LevelError, validDefaultConfig())
+	l.out = io.Discard
+	l.errOut = io.Discard
+	l.nop = true
+	return l
+}
+
+// Clone returns an independent Logger with l's current settings, for a
+// component that needs something different without calling Init again:
+//
+//	child := logr.Get().Clone()
+//	child.SetLevel(logr.LevelDebug)
+//
+// The clone copies the formatter, level and per-layer levels, layer
+// filters, default layer, config, allowed layers, layers and depths set
+// per package, outputs, sinks, error handler, hooks, processors, context
+// extractors, sampling rate and rate limits. Changes made afterwards to
+// either logger don't affect the other. The layer cache is not shared:
+// the clone starts with an empty one, as well as fresh sampling, rate
+// limit, Metrics and DedupConsecutive counters. Outputs and sinks are the
+// same writers, and closing the clone leaves them open. With Config.Async
+// the clone runs its own background writer.
+func (l *Logger) Clone() *Logger {
+	c := newLogger(nil, LevelInfo, l.config)
+
+	l.mu.Lock()
+	c.formatter = l.formatter
+	c.level = l.level
+	c.layerLevels = maps.Clone(l.layerLevels)
+	c.onlyLayers = maps.Clone(l.onlyLayers)
+	c.exceptLayers = maps.Clone(l.exceptLayers)
+	c.defaultLayer = l.defaultLayer
+	c.allowedLayers = maps.Clone(l.allowedLayers)
+	c.rejectedLayers = maps.Clone(l.rejectedLayers)
+	c.hasRejected.Store(len(c.rejectedLayers) > 0)
+	c.out = l.out
+	c.errOut = l.errOut
+	c.sinks = slices.Clone(l.sinks)
+	c.newOutputDedup()
+	for range c.sinks {
+		c.addSinkDedup()
+	}
+	c.headerDone = l.headerDone // Outputs are shared, so they have it already
+	c.errorHandler = l.errorHandler
+	c.extractors = slices.Clone(l.extractors)
+	if l.sampler != nil {
+		c.sampler = &sampler{every: l.sampler.every, sites: make(map[string]uint64)}
+	}
+	c.nop = l.nop
+	l.mu.Unlock()
+
+	l.registryMu.RLock()
+	c.registry = copyRegistry(l.registry)
+	l.registryMu.RUnlock()
+
+	l.hooks.mu.RLock()
+	c.hooks.funcs = slices.Clone(l.hooks.funcs)
+	c.hooks.processors = slices.Clone(l.hooks.processors)
+	l.hooks.mu.RUnlock()
+	c.retains.Store(l.retains.Load())
+
+	l.limiter.mu.Lock()
+	for layer, bucket := range l.limiter.buckets {
+		c.limiter.buckets[layer] = &tokenBucket{rate: bucket.rate, tokens: bucket.rate, last: nowFunc()}
+		c.limiter.dropped[layer] = new(atomic.Uint64)
+	}
+	l.limiter.mu.Unlock()
+
+	return c
+}
+
+// newLogger allocates a Logger's state. Callers validate config first.
+func newLogger(formatter Formatter, level Level, config Config) *Logger {
+	l := &Logger{
+		formatter: formatter,
+		level:     level,
+
+		config:     config,
+		redactor:   newRedactor(config),
+		limiter:    newRateLimiter(),
+		registry:   make(map[string]*packageConfig),
+		layerCache: newLayerCache(config.MaxCacheSize),
+
+		// Note: allowedLayers comes from config.allowedLayers
+		allowedLayers: make(map[Layer]int),
+
+		out:    os.Stdout,
+		errOut: os.Stderr,
+
+		errorHandler: stderrErrorHandler,
+	}
+
+	// If useing StrictMode, populate allowedLayers from config, with
+	// priorities in the order listed
+	if config.StrictMode {
+		for i, layer := range config.AllowedLayers {
+			if _, ok := l.allowedLayers[layer]; !ok {
+				l.allowedLayers[layer] = i
+			}
+		}
+	}
+
+	l.newOutputDedup()
+	if config.Async {
+		l.startAsync()
+	}
+	return l
+}
+
+// validDefaultConfig returns DefaultConfig after Validate, which detects
+// the module path for AutoModulePrefix.
+func validDefaultConfig() Config {
+	config := DefaultConfig()
+	if err := config.Validate(); err != nil {
+		panic(fmt.Sprintf("Invalid config: %v", err))
+	}
+	return config
+}
+
+// Get returns the global logger. It panics if Init or InitWithConfig
+// hasn't been called; GetOrDefault returns a fallback logger instead.
 func Get() *Logger {
+	globalMu.Lock()
+	defer globalMu.Unlock()
+
 	if defaultLogger == nil {
 		panic("Logger not initialized: call Init() before Get()")
 	}
 	return defaultLogger
 }
 
-func (l *Logger) SetLayer(layer Layer) {
+// GetOrDefault is like Get but never panics. Before Init or
+// InitWithConfig it returns a shared fallback logger writing plain text at
+// LevelInfo with DefaultConfig, so a library can log from its own init
+// functions whatever order packages are initialized in:
+//
+//	func init() {
+//		logr.GetOrDefault().Debug("registering drivers")
+//	}
+//
+// The fallback is separate from the global logger: a later Init still
+// takes effect, and GetOrDefault returns the global logger from then on.
+// Settings changed on the fallback are not carried over.
+func GetOrDefault() *Logger {
+	globalMu.Lock()
+	defer globalMu.Unlock()
+
+	if defaultLogger != nil {
+		return defaultLogger
+	}
+	if fallbackLogger == nil {
+		fallbackLogger = newLogger(&PlainTextFormatter{}, LevelInfo, validDefaultConfig())
+	}
+	return fallbackLogger
+}
+
+// RegisterLayer adds the layer for name (upper-cased) to the layers this
+// logger accepts in SetLayer and returns it. A new layer's priority is the
+// number of layers allowed before it, so layers sort in registration order
+// by default.
+func (l *Logger) RegisterLayer(name string) Layer {
+	layer := RegisterLayer(name)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.allowedLayers == nil {
+		l.allowedLayers = make(map[Layer]int)
+	}
+	if _, ok := l.allowedLayers[layer]; !ok {
+		l.allowedLayers[layer] = len(l.allowedLayers)
+	}
+	return layer
+}
+
+// SetLayerPriority sets the priority of an allowed layer. Priorities order
+// layers for display, lower first: Logger.AllowedLayers lists layers by
+// priority, and formatters, sinks and dashboards can use LayerPriority to
+// sort or group by layer. They don't affect filtering. It returns an error
+// wrapping ErrUnknownLayer for a layer that isn't allowed.
+func (l *Logger) SetLayerPriority(layer Layer, priority int) error {
 	l.mu.Lock()
 	defer l.mu.Unlock()
+
 	if _, ok := l.allowedLayers[layer]; !ok {
-		panic("Layer not found: create a new layer RegisterLayer()")
-	} else {
-		l.defaultLayer = layer
+		return fmt.Errorf("%w: %q; register it with Logger.RegisterLayer", ErrUnknownLayer, layer)
+	}
+	l.allowedLayers[layer] = priority
+	return nil
+}
+
+// LayerPriority returns the priority of layer as given to Init or set by
+// Config.AllowedLayers order, RegisterLayer or SetLayerPriority, and
+// whether the layer is allowed at all.
+func (l *Logger) LayerPriority(layer Layer) (int, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	priority, ok := l.allowedLayers[layer]
+	return priority, ok
+}
+
+// AllowedLayers returns the layers SetLayer accepts, ordered by priority
+// and then by name.
+func (l *Logger) AllowedLayers() []Layer {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	layers := slices.Collect(maps.Keys(l.allowedLayers))
+	slices.SortFunc(layers, func(a, b Layer) int {
+		if c := cmp.Compare(l.allowedLayers[a], l.allowedLayers[b]); c != 0 {
+			return c
+		}
+		return cmp.Compare(a, b)
+	})
+	return layers
+}
+
+// SetLayer sets the logger's default layer. It panics if the layer was not
+// allowed through Init, Config.AllowedLayers or RegisterLayer; SetLayerSafe
+// returns an error instead.
+//
+// In StrictMode a layer that isn't allowed is handled per
+// Config.StrictModeAction instead.
+func (l *Logger) SetLayer(layer Layer) {
+	if err := l.SetLayerSafe(layer); err != nil {
+		if l.config.StrictMode {
+			l.failLayer(err)
+			return
+		}
+		panic("Layer not found: register it with Logger.RegisterLayer()")
 	}
 }
 
+// SetLayerSafe is like SetLayer but returns an error wrapping
+// ErrUnknownLayer for a layer that isn't allowed instead of panicking.
+func (l *Logger) SetLayerSafe(layer Layer) error {
+	if l.config.StrictMode {
+		checked, err := l.checkLayer(layer)
+		if err != nil {
+			return err
+		}
+		layer = checked
+	} else if !l.isLayerAllowed(layer) {
+		return fmt.Errorf("%w: %q; register it with Logger.RegisterLayer", ErrUnknownLayer, layer)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.defaultLayer = layer
+	return nil
+}
+
+// SetOutput sends every log line, regardless of level, to w.
+// Passing nil restores the default stdout/stderr split.
+func (l *Logger) SetOutput(w io.Writer) {
+	l.SetOutputs(w, w)
+}
+
+// SetOutputs sets separate destinations for Debug/Info lines (out) and
+// Warn/Error lines (errOut). A nil writer falls back to os.Stdout or
+// os.Stderr respectively.
+func (l *Logger) SetOutputs(out, errOut io.Writer) {
+	// Wait for a write in progress, so the old writers are no longer in
+	// use once this returns
+	l.writeMu.Lock()
+	defer l.writeMu.Unlock()
+	// Pending DedupConsecutive summaries belong to the old writers
+	l.flushDedup()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if out == nil {
+		out = os.Stdout
+	}
+	if errOut == nil {
+		errOut = os.Stderr
+	}
+	l.out = out
+	l.errOut = errOut
+	l.newOutputDedup()
+}
+
+// AddSink registers an additional writer that receives every log line,
+// alongside the outputs set by SetOutput/SetOutputs.
+func (l *Logger) AddSink(w io.Writer) {
+	l.AddEntrySink(writerSink{w})
+}
+
+// AddEntrySink registers a Sink that receives every entry together with
+// its formatted line.
+func (l *Logger) AddEntrySink(s Sink) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, ok := s.(writerSink); !ok {
+		l.retains.Store(true)
+	}
+	l.sinks = append(l.sinks, s)
+	l.addSinkDedup()
+}
+
+// SetErrorHandler installs a callback for formatter failures and write
+// failures on outputs and sinks. By default errors are printed to
+// os.Stderr; passing nil restores that default. The handler may run while
+// the logger is locked, so it must not log through the same Logger.
+func (l *Logger) SetErrorHandler(handler ErrorHandler) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if handler == nil {
+		handler = stderrErrorHandler
+	}
+	l.errorHandler = handler
+}
+
+// Close flushes queued lines, writes the summary of any pending
+// DedupConsecutive repeats and turns every later logging call into a
+// no-op. It is idempotent and safe to defer in main.
+//
+// The logger doesn't own any writer: outputs set with SetOutput or
+// SetOutputs and sinks added with AddSink are borrowed and left open, so
+// close sinks such as FileSink or NetworkSink after the logger. The error
+// is always nil; Close returns one so a Logger is an io.Closer.
+func (l *Logger) Close() error {
+	if l.closed.Swap(true) {
+		return nil
+	}
+
+	if l.async != nil {
+		l.async.close()
+	}
+
+	l.writeMu.Lock()
+	defer l.writeMu.Unlock()
+	l.flushDedup()
+	return nil
+}
+
+// SetLevel changes the minimum level at runtime.
+func (l *Logger) SetLevel(level Level) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.level = level
+}
+
+// SetLevelForLayer sets a minimum level for a single layer that takes
+// precedence over the global level, e.g. keep DB at Warn while HTTP logs
+// at Info.
+func (l *Logger) SetLevelForLayer(layer Layer, level Level) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.layerLevels == nil {
+		l.layerLevels = make(map[Layer]Level)
+	}
+	l.layerLevels[layer] = level
+}
+
+// OnlyLayers drops every entry whose layer is not one of layers, whatever
+// its level, e.g. to see only DB lines while debugging. It replaces any
+// earlier OnlyLayers call; with no layers it lets every layer through
+// again. Layers are matched after resolution, as written.
+func (l *Logger) OnlyLayers(layers ...Layer) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.onlyLayers = layerSet(layers)
+}
+
+// ExceptLayers drops every entry whose layer is one of layers. It replaces
+// any earlier ExceptLayers call; with no layers it drops nothing. When
+// combined with OnlyLayers, a layer must pass both.
+func (l *Logger) ExceptLayers(layers ...Layer) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.exceptLayers = layerSet(layers)
+}
+
+// ClearLayerFilters removes the filters set by OnlyLayers and ExceptLayers.
+func (l *Logger) ClearLayerFilters() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.onlyLayers = nil
+	l.exceptLayers = nil
+}
+
+func layerSet(layers []Layer) map[Layer]bool {
+	if len(layers) == 0 {
+		return nil
+	}
+	set := make(map[Layer]bool, len(layers))
+	for _, layer := range layers {
+		set[layer] = true
+	}
+	return set
+}
+
+// filtersOut reports whether OnlyLayers or ExceptLayers drop layer.
+func (l *Logger) filtersOut(layer Layer) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.onlyLayers != nil && !l.onlyLayers[layer] {
+		return true
+	}
+	return l.exceptLayers[layer]
+}
+
+// GetLevel returns the current minimum level.
+func (l *Logger) GetLevel() Level {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.level
+}
+
 func (l *Logger) Info(msg string) {
-	l.log(LevelInfo, msg)
+	l.log(LevelInfo, msg, nil)
 }
 
 func (l *Logger) Error(msg string) {
-	l.log(LevelError, msg)
+	l.log(LevelError, msg, nil)
 }
 
 func (l *Logger) Debug(msg string) {
-	l.log(LevelDebug, msg)
+	l.log(LevelDebug, msg, nil)
 }
 
 func (l *Logger) Warn(msg string) {
-	l.log(LevelWarn, msg)
+	l.log(LevelWarn, msg, nil)
 }
 
+// ErrorErr writes msg at LevelError with err kept apart from the message in
+// LogEntry.Err, so formatters render it as its own field, e.g.
+// error="connection refused", and it can be filtered on. A nil err logs
+// msg alone.
+func (l *Logger) ErrorErr(msg string, err error) {
+	l.logErrAs("", LevelError, msg, err, nil)
+}
+
+// Test writes msg at LevelTest. It does nothing unless Config.TestMode is
+// set, and is not filtered by the logger's level when it is.
 func (l *Logger) Test(msg string) {
-	l.log(LevelTest, msg)
+	l.log(LevelTest, msg, nil)
+}
+
+func (l *Logger) Trace(msg string) {
+	l.log(LevelTrace, msg, nil)
 }
 
 // Dynamic context
 
 func (l *Logger) Errorf(format string, args ...any) {
-	l.log(LevelError, fmt.Sprintf(format, args...))
+	l.log(LevelError, fmt.Sprintf(format, args...), nil)
 }
 
 func (l *Logger) Infof(format string, args ...any) {
-	l.log(LevelInfo, fmt.Sprintf(format, args...))
+	l.log(LevelInfo, fmt.Sprintf(format, args...), nil)
 }
 
 func (l *Logger) Debugf(format string, args ...any) {
-	l.log(LevelDebug, fmt.Sprintf(format, args...))
+	l.log(LevelDebug, fmt.Sprintf(format, args...), nil)
 }
 
 func (l *Logger) Warnf(format string, args ...any) {
-	l.log(LevelWarn, fmt.Sprintf(format, args...))
+	l.log(LevelWarn, fmt.Sprintf(format, args...), nil)
+}
+
+func (l *Logger) Tracef(format string, args ...any) {
+	l.log(LevelTrace, fmt.Sprintf(format, args...), nil)
+}
+
+// Print-style: the ln-variants join their arguments like fmt.Sprintln,
+// with spaces between them and no trailing newline, e.g.
+// logger.Infoln("cache miss for", key, "after", elapsed).
+
+func (l *Logger) Errorln(args ...any) {
+	l.log(LevelError, sprintln(args...), nil)
+}
+
+func (l *Logger) Infoln(args ...any) {
+	l.log(LevelInfo, sprintln(args...), nil)
+}
+
+func (l *Logger) Debugln(args ...any) {
+	l.log(LevelDebug, sprintln(args...), nil)
+}
+
+func (l *Logger) Warnln(args ...any) {
+	l.log(LevelWarn, sprintln(args...), nil)
+}
+
+func (l *Logger) Traceln(args ...any) {
+	l.log(LevelTrace, sprintln(args...), nil)
+}
+
+// sprintln is fmt.Sprintln without the newline, which the output adds
+// after each entry.
+func sprintln(args ...any) string {
+	msg := fmt.Sprintln(args...)
+	return msg[:len(msg)-1]
+}
+
+// Key-value context: the w-variants take alternating keys and values,
+// e.g. logger.Infow("request processed", "userID", 456, "ms", 12).
+
+func (l *Logger) Infow(msg string, keysAndValues ...any) {
+	l.log(LevelInfo, msg, metadataFromPairs(keysAndValues))
 }
 
-func (l *Logger) log(level Level, msg string) {
-	if l.level <= level {
-		layerStr := l.getOrResolveLayer()
-		layer := Layer(layerStr)
+func (l *Logger) Errorw(msg string, keysAndValues ...any) {
+	l.log(LevelError, msg, metadataFromPairs(keysAndValues))
+}
+
+func (l *Logger) Debugw(msg string, keysAndValues ...any) {
+	l.log(LevelDebug, msg, metadataFromPairs(keysAndValues))
+}
+
+func (l *Logger) Warnw(msg string, keysAndValues ...any) {
+	l.log(LevelWarn, msg, metadataFromPairs(keysAndValues))
+}
+
+func (l *Logger) Tracew(msg string, keysAndValues ...any) {
+	l.log(LevelTrace, msg, metadataFromPairs(keysAndValues))
+}
+
+// meta is owned by the logger: callers build it for this call only, so it
+// is recycled once the entry has been written.
+func (l *Logger) log(level Level, msg string, meta *Metadata) {
+	l.logAs("", level, msg, meta)
+}
+
+// logAs is log with a layer chosen by the caller, e.g. with WithLayer.
+// That layer is used as given and the calling package is only looked up
+// when the caller's file and line are needed; an empty layer is resolved
+// from the calling package.
+func (l *Logger) logAs(layer Layer, level Level, msg string, meta *Metadata) {
+	l.logErrAs(layer, level, msg, nil, meta)
+}
 
-		entry := NewEntry(level, layer, msg)
-		formatted := l.formatter.Format(*entry)
-		fmt.Println(formatted)
+// logErrAs is logAs with an error recorded in LogEntry.Err.
+func (l *Logger) logErrAs(layer Layer, level Level, msg string, err error, meta *Metadata) {
+	if l.closed.Load() || !l.mayLog(level) {
+		releaseMetadata(meta)
+		return
 	}
+
+	if layer != "" && l.config.StrictMode {
+		checked, err := l.checkLayer(layer)
+		if err != nil {
+			releaseMetadata(meta)
+			l.failLayer(err)
+			return
+		}
+		layer = checked
+	}
+
+	var file string
+	var line int
+	if layer == "" {
+		var layerStr string
+		layerStr, file, line = l.getOrResolveLayer()
+		layer = Layer(layerStr)
+	} else if l.needsCaller() {
+		_, file, line = getCaller(1)
+	}
+	entry := LogEntry{Level: level, Layer: layer, Message: msg, Err: err, Metadata: meta}
+	l.emit(entry, file, line)
+	l.releaseOwned(meta)
 }
 
-func InitWithConfig(formatter Formatter, level Level, config Config) *Logger {
-	if err := config.Validate(); err != nil {
-		panic(fmt.Sprintf("Invalid config: %v", err))
+// needsCaller reports whether emit uses the call site, for IncludeCaller
+// or to sample per call site.
+func (l *Logger) needsCaller() bool {
+	if l.config.IncludeCaller {
+		return true
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.sampler != nil
+}
+
+// Log writes an entry built by the caller, e.g. one with its own timestamp
+// or one replayed from storage. It goes through the same level filtering,
+// sampling, rate limits, redaction, processors and hooks as Info and the
+// other methods. An empty Layer is resolved from the calling package and a
+// zero Timestamp is set to the current time.
+func (l *Logger) Log(entry LogEntry) {
+	if l.closed.Load() || !l.mayLog(entry.Level) {
+		return
 	}
 
-	once.Do(func() {
-		defaultLogger = &Logger{
-			formatter: formatter,
-			level:     level,
+	if entry.Layer != "" && l.config.StrictMode {
+		checked, err := l.checkLayer(entry.Layer)
+		if err != nil {
+			l.failLayer(err)
+			return
+		}
+		entry.Layer = checked
+	}
+
+	packagePath, file, line := getCaller(1)
+	if entry.Layer == "" {
+		entry.Layer = Layer(resolveLayer(l, packagePath))
+	}
+	l.emit(entry, file, line)
+}
+
+// emit applies the layer threshold, completes the entry and writes it.
+// file and line are the logging call site.
+func (l *Logger) emit(entry LogEntry, file string, line int) {
+	level, layer, meta := entry.Level, entry.Layer, entry.Metadata
+	if level != LevelTest && level < l.thresholdFor(layer) {
+		return
+	}
+	if l.filtersOut(layer) || l.dropsLayer(layer) {
+		return
+	}
+
+	if s := l.samplerFor(level); s != nil {
+		keep, skipped := s.sample(file, line)
+		if !keep {
+			return
+		}
+		if skipped > 0 {
+			if meta == nil {
+				meta = NewMetadata()
+			} else {
+				meta = meta.Clone()
+			}
+			meta.Add(SuppressedKey, skipped)
+		}
+	}
+
+	if (!level.atLeast(LevelWarn) || !l.config.RateLimitBypassWarn) && !l.limiter.allow(layer) {
+		return
+	}
+
+	if entry.Timestamp.IsZero() {
+		entry.Timestamp = nowFunc()
+	}
+	entry.Metadata = l.redactor.redact(resolveLazy(meta, l.reportError))
+	entry.timeFormat = l.config.TimeFormat
+	entry.escapeNewlines = l.config.EscapeNewlines
+	if l.config.UTC {
+		entry.Timestamp = entry.Timestamp.UTC()
+	}
+	if l.config.IncludeCaller && entry.File == "" && file != "" {
+		entry.File = path.Base(slashPath(file))
+		entry.Line = line
+	}
+	if l.config.CaptureStackOnError && level.atLeast(LevelError) && entry.Stack == "" {
+		entry.Stack = captureStack()
+	}
+	// Processors get a pooled copy so entry itself stays off the heap
+	e := acquireEntry(entry)
+	keep := l.runProcessors(e)
+	entry = *e
+	releaseEntry(e)
+	if !keep {
+		return
+	}
+	l.runHooks(entry)
+	// Formatted without holding l.mu, so goroutines only contend for the
+	// write; this is why a Formatter must be safe for concurrent use
+	formatted, err := l.format(entry)
+	if err != nil {
+		l.reportError(err)
+		return
+	}
+	l.metrics.count(entry.Level, entry.Layer)
 
-			config:     config,
-			registry:   make(map[string]*packageConfig),
-			layerCache: make(map[string]string),
+	if l.async != nil {
+		l.async.enqueue(entry, formatted)
+		return
+	}
+
+	l.write(entry, formatted)
+}
 
-			// Note: allowedLayers comes from config.allowedLayers
-			allowedLayers: make(map[Layer]int),
+// format renders entry, using TryFormat when the formatter can report
+// failures so a broken entry is reported instead of written as garbage.
+func (l *Logger) format(entry LogEntry) (string, error) {
+	if tf, ok := l.formatter.(TryFormatter); ok {
+		formatted, err := tf.TryFormat(entry)
+		if err != nil {
+			return "", fmt.Errorf("logr: format entry: %w", err)
 		}
+		return formatted, nil
+	}
+	return l.formatter.Format(entry), nil
+}
+
+// samplerFor returns the sampler that applies to level, if any. Warn and
+// above are never sampled.
+func (l *Logger) samplerFor(level Level) *sampler {
+	if level.atLeast(LevelWarn) {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.sampler
+}
+
+// Enabled reports whether a line at level from the calling package would
+// be written, so expensive arguments are only built when they are used:
+//
+//	if logger.Enabled(logr.LevelDebug) {
+//		logger.Debugf("state: %s", dump(state))
+//	}
+//
+// Levels set with SetLevelForLayer are taken into account, which resolves
+// the caller's layer just as a log call would. Sampling and rate limits
+// are not: a line that is enabled may still be dropped by them.
+func (l *Logger) Enabled(level Level) bool {
+	return l.enabledFor("", level)
+}
+
+// enabledFor is Enabled for a line written under layer, or under the
+// calling package's layer when layer is empty.
+func (l *Logger) enabledFor(layer Layer, level Level) bool {
+	if l.closed.Load() || !l.mayLog(level) {
+		return false
+	}
+	if level == LevelTest {
+		return true
+	}
+
+	l.mu.Lock()
+	perLayer := len(l.layerLevels) > 0
+	l.mu.Unlock()
+	if !perLayer {
+		return true
+	}
+
+	if layer == "" {
+		layerStr, _, _ := l.getOrResolveLayer()
+		layer = Layer(layerStr)
+	}
+	return level >= l.thresholdFor(layer)
+}
+
+// mayLog is a cheap pre-check done before resolving the layer. Without
+// per-layer levels it applies the global level; otherwise the decision
+// has to wait until the layer is known.
+func (l *Logger) mayLog(level Level) bool {
+	if l.nop {
+		return false
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if level == LevelTest {
+		return l.config.TestMode
+	}
+	return len(l.layerLevels) > 0 || l.level.threshold() <= level
+}
+
+// thresholdFor returns the minimum level for layer: its own level if set
+// with SetLevelForLayer, otherwise the global level.
+func (l *Logger) thresholdFor(layer Layer) Level {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if level, ok := l.layerLevels[layer]; ok {
+		return level.threshold()
+	}
+	return l.level.threshold()
+}
 
-		// If useing StrictMode, populate allowedLayers from config
-		if config.StrictMode {
-			for _, layer := range config.AllowedLayers {
-				defaultLogger.allowedLayers[layer] = 1
+// write sends one formatted line to the level's output and to every sink.
+// writeMu keeps concurrent lines from interleaving and the header ahead of
+// them; l.mu is only held to snapshot the destinations, so a slow writer
+// doesn't hold up level checks and settings in other goroutines.
+func (l *Logger) write(entry LogEntry, formatted string) {
+	l.writeMu.Lock()
+	defer l.writeMu.Unlock()
+	l.deliver(entry, formatted)
+}
+
+// destinations snapshots where a line at level should go. Callers must
+// hold l.mu; the returned value can be used after the lock is released.
+// The first snapshot also carries the formatter's header, if it has one.
+func (l *Logger) destinations(level Level) destinations {
+	d := destinations{
+		primary:      l.writerFor(level),
+		sinks:        l.sinks[:len(l.sinks):len(l.sinks)],
+		errorHandler: l.errorHandler,
+	}
+	if l.config.DedupConsecutive {
+		d.dedup = l.dedupFor(level)
+		d.sinkDedup = l.sinkDedup[:len(l.sinkDedup):len(l.sinkDedup)]
+	}
+	if !l.headerDone {
+		l.headerDone = true
+		if he, ok := l.formatter.(HeaderEmitter); ok {
+			if header, ok := he.Header(); ok {
+				d.header = header
+				d.headerTo = l.headerWriters()
 			}
 		}
+	}
+	return d
+}
+
+// headerWriters lists the outputs and AddSink writers, each once. Callers
+// must hold l.mu.
+func (l *Logger) headerWriters() []io.Writer {
+	writers := []io.Writer{l.out}
+	if !sameWriter(l.errOut, l.out) {
+		writers = append(writers, l.errOut)
+	}
+	for _, sink := range l.sinks {
+		if ws, ok := sink.(writerSink); ok && !slices.ContainsFunc(writers, func(w io.Writer) bool { return sameWriter(w, ws.w) }) {
+			writers = append(writers, ws.w)
+		}
+	}
+	return writers
+}
+
+// sameWriter reports whether a and b are the same writer, treating writers
+// that can't be compared as different.
+func sameWriter(a, b io.Writer) (same bool) {
+	defer func() {
+		if recover() != nil {
+			same = false
+		}
+	}()
+	return a == b
+}
+
+// destinations is the set of writers a single line is sent to.
+type destinations struct {
+	primary      io.Writer
+	sinks        []Sink
+	errorHandler ErrorHandler
+
+	header   string      // HeaderEmitter header, written before the line
+	headerTo []io.Writer // Where the header goes
+
+	// Config.DedupConsecutive state for primary and each of sinks
+	dedup     *deduper
+	sinkDedup []*deduper
+}
+
+// write sends the line to every writer, skipping primary when it is nil. A
+// failing writer does not stop the others; each error is reported to the
+// error handler, if any.
+func (d destinations) write(entry LogEntry, formatted string) {
+	for _, w := range d.headerTo {
+		if _, err := io.WriteString(w, d.header+"\n"); err != nil {
+			d.handleError(fmt.Errorf("logr: write header: %w", err))
+		}
+	}
+	if d.primary != nil {
+		if err := writeLine(d.primary, entry.Level, formatted); err != nil {
+			d.handleError(fmt.Errorf("logr: write to output: %w", err))
+		}
+	}
+	for i, sink := range d.sinks {
+		if err := sink.Write(entry, formatted); err != nil {
+			d.handleError(fmt.Errorf("logr: write to sink %d: %w", i, err))
+		}
+	}
+}
+
+func (d destinations) handleError(err error) {
+	if d.errorHandler != nil {
+		d.errorHandler(err)
+	}
+}
+
+// writerFor picks the destination for a level. Callers must hold l.mu.
+func (l *Logger) writerFor(level Level) io.Writer {
+	if level.atLeast(LevelWarn) {
+		return l.errOut
+	}
+	return l.out
+}
+
+func InitWithConfig(formatter Formatter, level Level, config Config) *Logger {
+	if err := config.Validate(); err != nil {
+		panic(fmt.Sprintf("Invalid config: %v", err))
+	}
+
+	globalMu.Lock()
+	defer globalMu.Unlock()
+
+	once.Do(func() {
+		defaultLogger = newLogger(formatter, level, config)
 	})
 	return defaultLogger
 }
 
+// Reset closes the global logger and forgets it, so the next Init or
+// InitWithConfig builds a new one and Get panics until then. It is meant
+// for tests that reconfigure the global logger between cases.
+func Reset() error {
+	globalMu.Lock()
+	defer globalMu.Unlock()
+
+	var err error
+	if defaultLogger != nil {
+		err = defaultLogger.Close()
+	}
+	defaultLogger = nil
+	once = sync.Once{}
+	return err
+}
+
 // SetLayerForPackage stores a custom layer name for a specific package.
 // This is called by the user at the top of their package file.
+// In StrictMode a layer that isn't allowed is handled per
+// Config.StrictModeAction, which panics by default;
+// SetLayerForPackageSafe returns an error instead.
 func (l *Logger) SetLayerForPackage(layer string) {
 	// Detect which package is calling this function
-	// We skip 2 frames: [0]=runtime.Caller, [1]=getCurrentPackage, [2]=SetLayerForPackage, [3]=actual caller
-	packagePath := getCurrentPackage(skipForSetMethods)
+	packagePath := getCurrentPackage(1)
+	if err := l.setLayerForPath(packagePath, layer); err != nil {
+		l.failLayer(err)
+	}
+}
+
+// SetLayerForPackageSafe is like SetLayerForPackage but returns an error
+// wrapping ErrUnknownLayer instead of panicking.
+func (l *Logger) SetLayerForPackageSafe(layer string) error {
+	return l.setLayerForPath(getCurrentPackage(1), layer)
+}
+
+// setLayerForPath stores layer for packagePath and invalidates the cached
+// layer of the package and of every package beneath it, since children
+// inherit it through findInheritedLayer. In StrictMode the layer is
+// checked with checkLayer.
+func (l *Logger) setLayerForPath(packagePath string, layer string) error {
+	checked, err := l.checkLayer(Layer(layer))
+	if err != nil {
+		return err
+	}
+	layer = string(checked)
 
 	// Thread-safe write to registry
 	l.registryMu.Lock()
@@ -156,12 +1068,107 @@ func (l *Logger) SetLayerForPackage(layer string) {
 	// Store the layer name
 	l.registry[packagePath].explicitLayer = &layer
 
-	// Invalidate cache for this package (it needs to be recalculated)
-	delete(l.layerCache, packagePath)
+	// Invalidate cache for this package and its children (they need to be recalculated)
+	l.layerCache.removeTree(packagePath)
+	return nil
+}
+
+// isLayerAllowed reports whether layer was allowed through Init,
+// Config.AllowedLayers or RegisterLayer.
+func (l *Logger) isLayerAllowed(layer Layer) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_, ok := l.allowedLayers[layer]
+	return ok
+}
+
+// checkLayer applies Config.StrictModeAction to a layer being assigned. It
+// returns the layer to use in its place, or an error wrapping
+// ErrUnknownLayer when the assignment must be rejected. Outside StrictMode,
+// and for allowed layers, layer is returned unchanged.
+func (l *Logger) checkLayer(layer Layer) (Layer, error) {
+	if !l.config.StrictMode || l.isLayerAllowed(layer) {
+		return layer, nil
+	}
+
+	switch l.config.StrictModeAction {
+	case StrictWarn:
+		fallback := Layer(l.config.fallbackLayer())
+		if l.rejectLayer(layer) {
+			l.warnRejected(layer, fallback)
+		}
+		return fallback, nil
+	case StrictDrop:
+		l.rejectLayer(layer)
+		return layer, nil
+	default:
+		return "", fmt.Errorf("%w: %q is not in AllowedLayers", ErrUnknownLayer, layer)
+	}
+}
+
+// failLayer handles a layer rejected by checkLayer where there is no error
+// to return: it panics with StrictPanic and reports err otherwise.
+func (l *Logger) failLayer(err error) {
+	if l.config.StrictModeAction == StrictPanic {
+		panic(err.Error())
+	}
+	l.reportError(err)
+}
+
+// rejectLayer records layer in rejectedLayers and reports whether it is
+// new there.
+func (l *Logger) rejectLayer(layer Layer) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.rejectedLayers[layer] {
+		return false
+	}
+	if l.rejectedLayers == nil {
+		l.rejectedLayers = make(map[Layer]bool)
+	}
+	l.rejectedLayers[layer] = true
+	l.hasRejected.Store(true)
+	return true
+}
+
+// warnRejected logs the StrictWarn warning for layer, under fallback. With
+// Config.IncludeCaller the warning points at the call that assigned layer.
+func (l *Logger) warnRejected(layer, fallback Layer) {
+	if l.closed.Load() || !l.mayLog(LevelWarn) {
+		return
+	}
+	meta := NewMetadata()
+	meta.Add("layer", string(layer))
+	meta.Add("fallback", string(fallback))
+	entry := LogEntry{
+		Level:    LevelWarn,
+		Layer:    fallback,
+		Message:  "logr: layer not in AllowedLayers, using the fallback layer",
+		Metadata: meta,
+	}
+	var file string
+	var line int
+	if l.config.IncludeCaller {
+		_, file, line = getCaller(1)
+	}
+	l.emit(entry, file, line)
+}
+
+// dropsLayer reports whether entries under layer are dropped by
+// StrictDrop.
+func (l *Logger) dropsLayer(layer Layer) bool {
+	if l.config.StrictModeAction != StrictDrop || !l.hasRejected.Load() {
+		return false
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_, allowed := l.allowedLayers[layer]
+	return l.rejectedLayers[layer] && !allowed
 }
 
 // SetDepth sets a custom depth for layer extraction in the calling package.
 // Unlike SetLayerForPackage, this does NOT inherit to child packages.
+// It panics if depth is negative; SetDepthSafe returns an error instead.
 func (l *Logger) SetDepth(depth int) {
 	// Validate depth
 	if depth < 0 {
@@ -169,8 +1176,21 @@ func (l *Logger) SetDepth(depth int) {
 	}
 
 	// Detect calling package
-	packagePath := getCurrentPackage(skipForSetMethods)
+	l.setDepthForPath(getCurrentPackage(1), depth)
+}
+
+// SetDepthSafe is like SetDepth but returns an error wrapping
+// ErrInvalidDepth for a negative depth instead of panicking.
+func (l *Logger) SetDepthSafe(depth int) error {
+	if depth < 0 {
+		return fmt.Errorf("%w: must be >= 0, got %d", ErrInvalidDepth, depth)
+	}
+
+	l.setDepthForPath(getCurrentPackage(1), depth)
+	return nil
+}
 
+func (l *Logger) setDepthForPath(packagePath string, depth int) {
 	// Thread-safe write
 	l.registryMu.Lock()
 	defer l.registryMu.Unlock()
@@ -184,20 +1204,54 @@ func (l *Logger) SetDepth(depth int) {
 	l.registry[packagePath].explicitDepth = &depth
 
 	// Invalidate cache
-	delete(l.layerCache, packagePath)
+	l.layerCache.remove(packagePath)
 }
 
-// GetOrResolveLayer resolves the layer for the calling package.
-// This is an internal helper used by Log() method.
-func (l *Logger) getOrResolveLayer() string {
-	// Detect calling package (adjust skip as needed based on call stack)
-	packagePath := getCurrentPackage(skipForLogging)
+// ResolveLayerFor returns the layer a log call from packagePath would be
+// written under, without logging anything. It goes through the same
+// cache, SetLayerForPackage inheritance and SetDepth settings as a real
+// call, and caches the result too. Use it in tests or a startup check:
+//
+//	for _, pkg := range []string{"github.com/myapp/internal/api", "github.com/myapp/db/postgres"} {
+//		fmt.Println(pkg, "→", logger.ResolveLayerFor(pkg))
+//	}
+func (l *Logger) ResolveLayerFor(packagePath string) string {
+	return resolveLayer(l, packagePath)
+}
+
+// Registry returns a snapshot of the packages configured with
+// SetLayerForPackage or SetDepth, keyed by package path. The map is a copy:
+// changing it doesn't affect the logger, and later settings don't show up
+// in it. Packages that only inherit a layer from a parent are not listed;
+// use ResolveLayerFor to see the layer they end up with.
+func (l *Logger) Registry() map[string]PackageLayerInfo {
+	l.registryMu.RLock()
+	defer l.registryMu.RUnlock()
+
+	snapshot := make(map[string]PackageLayerInfo, len(l.registry))
+	for packagePath, pc := range l.registry {
+		var info PackageLayerInfo
+		if pc.explicitLayer != nil {
+			info.Layer, info.HasLayer = *pc.explicitLayer, true
+		}
+		if pc.explicitDepth != nil {
+			info.Depth, info.HasDepth = *pc.explicitDepth, true
+		}
+		snapshot[packagePath] = info
+	}
+	return snapshot
+}
 
-	// fmt.Printf("DEBUG: Detected package: %s\n", packagePath) // Add this temporarily
+// GetOrResolveLayer resolves the layer for the calling package.
+// This is an internal helper used by Log() method. The caller's file and
+// line come from the same stack frame and are returned alongside.
+func (l *Logger) getOrResolveLayer() (string, string, int) {
+	// Detect calling package, skipping logr's own frames
+	packagePath, file, line := getCaller(1)
 
 	// Try to resolve the layer
 	// (We'll implement resolveLayer in Phase 2, for now return placeholder)
 	layer := resolveLayer(l, packagePath)
 
-	return layer
+	return layer, file, line
 }
@@ -1,24 +1,36 @@
 package logr
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
+	"reflect"
+	"runtime"
+	"slices"
+	"strings"
 	"sync"
 	"testing"
+	"time"
 )
 
+// MockFormatter records the last message it formatted. Like any
+// Formatter it may be called concurrently, so the write is guarded.
 type MockFormatter struct {
+	mu            sync.Mutex
 	LastFormatted string
 }
 
 func (f *MockFormatter) Format(entry LogEntry) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
 	f.LastFormatted = entry.Message
 	return entry.Message
 }
 
 func resetLogger() {
 	// Reset singleton for fresh initialization
-	defaultLogger = nil
-	once = sync.Once{}
+	Reset()
 }
 
 func TestLoggerInfo(t *testing.T) {
@@ -75,6 +87,113 @@ func TestLoggerDebugWarnTest(t *testing.T) {
 	}
 }
 
+func TestLoggerTestRequiresTestMode(t *testing.T) {
+	mock := &MockFormatter{}
+
+	logger := New(mock, LevelDebug, DefaultConfig())
+	logger.Test("hidden")
+	if mock.LastFormatted != "" {
+		t.Errorf("expected Test to be dropped without TestMode, got %q", mock.LastFormatted)
+	}
+
+	config := DefaultConfig()
+	config.TestMode = true
+	logger = New(mock, LevelError, config)
+	logger.Test("visible")
+	if mock.LastFormatted != "visible" {
+		t.Errorf("expected Test to ignore the level in TestMode, got %q", mock.LastFormatted)
+	}
+}
+
+func TestLoggerLevelTestDoesNotSuppressError(t *testing.T) {
+	mock := &MockFormatter{}
+
+	logger := New(mock, LevelTest, DefaultConfig())
+	logger.Warn("warn")
+	if mock.LastFormatted != "" {
+		t.Errorf("expected Warn to be dropped at LevelTest, got %q", mock.LastFormatted)
+	}
+
+	logger.Error("error")
+	if mock.LastFormatted != "error" {
+		t.Errorf("expected Error to be logged at LevelTest, got %q", mock.LastFormatted)
+	}
+
+	mock.LastFormatted = ""
+	logger.SetLevel(LevelInfo)
+	logger.SetLevelForLayer(LayerDB, LevelTest)
+	logger.emit(LogEntry{Level: LevelError, Layer: LayerDB, Message: "db error"}, "", 0)
+	if mock.LastFormatted != "db error" {
+		t.Errorf("expected Error to pass a LevelTest layer threshold, got %q", mock.LastFormatted)
+	}
+}
+
+func TestLoggerTestIsNotAnError(t *testing.T) {
+	setNow(t, time.Date(2025, 9, 29, 12, 0, 0, 0, time.UTC))
+
+	config := DefaultConfig()
+	config.TestMode = true
+	config.CaptureStackOnError = true
+	config.RateLimitBypassWarn = true
+	logger := New(&MockFormatter{}, LevelInfo, config)
+	var out, errOut bytes.Buffer
+	logger.SetOutputs(&out, &errOut)
+	routeTestPackageTo(logger, LayerDB)
+	var stacks []string
+	logger.AddHook(func(e LogEntry) { stacks = append(stacks, e.Stack) })
+
+	logger.Test("hello")
+	if out.String() != "hello\n" || errOut.Len() != 0 {
+		t.Errorf("expected Test on the normal output, got out %q, errOut %q", out.String(), errOut.String())
+	}
+	if len(stacks) != 1 || stacks[0] != "" {
+		t.Errorf("expected no stack on a Test line, got %q", stacks)
+	}
+
+	out.Reset()
+	logger.SampleEvery(2)
+	for range 4 {
+		logger.Test("sampled") // one call site
+	}
+	if got := strings.Count(out.String(), "\n"); got != 2 {
+		t.Errorf("expected Test lines to be sampled, got %d of 4", got)
+	}
+
+	out.Reset()
+	logger.SampleEvery(0)
+	logger.SetRateLimit(LayerDB, 1)
+	for range 3 {
+		logger.Test("limited")
+	}
+	if got := strings.Count(out.String(), "\n"); got != 1 {
+		t.Errorf("expected Test lines to stay rate limited, got %d of 3", got)
+	}
+}
+
+func TestLoggerTrace(t *testing.T) {
+	mock := &MockFormatter{}
+
+	logger := New(mock, LevelDebug, DefaultConfig())
+	logger.Trace("hidden")
+	if mock.LastFormatted != "" {
+		t.Errorf("expected Trace not to be logged at LevelDebug, got %q", mock.LastFormatted)
+	}
+
+	logger.SetLevel(LevelTrace)
+	logger.Tracef("enter %s", "handler")
+	if mock.LastFormatted != "enter handler" {
+		t.Errorf("expected %q, got %q", "enter handler", mock.LastFormatted)
+	}
+
+	var buf bytes.Buffer
+	logger.formatter = &PlainTextFormatter{}
+	logger.SetOutput(&buf)
+	logger.Trace("exit handler")
+	if !strings.HasPrefix(buf.String(), "[TRACE]") {
+		t.Errorf("expected a TRACE line, got %q", buf.String())
+	}
+}
+
 func TestLoggerLevelFiltering(t *testing.T) {
 	resetLogger()
 
@@ -121,6 +240,812 @@ func TestLoggerSetLayer(t *testing.T) {
 	logger.SetLayer("UNKNOWN")
 }
 
+func TestLoggerRegisterLayerAllowsSetLayer(t *testing.T) {
+	resetLogger()
+
+	logger := Init(&MockFormatter{}, LevelInfo, nil)
+
+	cache := logger.RegisterLayer("cache")
+	if cache != Layer("CACHE") {
+		t.Errorf("RegisterLayer() = %q, want %q", cache, "CACHE")
+	}
+
+	// Must not panic now that the layer is registered
+	logger.SetLayer(cache)
+	if logger.defaultLayer != cache {
+		t.Errorf("expected defaultLayer %q, got %q", cache, logger.defaultLayer)
+	}
+
+	// Registering twice is harmless
+	if again := logger.RegisterLayer("CACHE"); again != cache {
+		t.Errorf("RegisterLayer() again = %q, want %q", again, cache)
+	}
+}
+
+func TestLayerPriority(t *testing.T) {
+	logger := New(&MockFormatter{}, LevelInfo, DefaultConfig())
+
+	db := logger.RegisterLayer("db")
+	http := logger.RegisterLayer("http")
+	cache := logger.RegisterLayer("cache")
+
+	// Registration order by default
+	if got := logger.AllowedLayers(); !slices.Equal(got, []Layer{db, http, cache}) {
+		t.Errorf("AllowedLayers() = %v, want registration order", got)
+	}
+
+	if err := logger.SetLayerPriority(cache, -1); err != nil {
+		t.Fatalf("SetLayerPriority() error = %v", err)
+	}
+	if err := logger.SetLayerPriority(http, 0); err != nil {
+		t.Fatalf("SetLayerPriority() error = %v", err)
+	}
+	if got := logger.AllowedLayers(); !slices.Equal(got, []Layer{cache, db, http}) {
+		t.Errorf("AllowedLayers() = %v, want by priority, then name", got)
+	}
+	if priority, ok := logger.LayerPriority(cache); !ok || priority != -1 {
+		t.Errorf("LayerPriority(CACHE) = %d, %v, want -1, true", priority, ok)
+	}
+
+	if _, ok := logger.LayerPriority("QUEUE"); ok {
+		t.Error("expected LayerPriority to report an unknown layer")
+	}
+	if err := logger.SetLayerPriority("QUEUE", 1); !errors.Is(err, ErrUnknownLayer) {
+		t.Errorf("SetLayerPriority() for an unknown layer error = %v, want ErrUnknownLayer", err)
+	}
+}
+
+func TestLayerPriorityFromInitAndStrictMode(t *testing.T) {
+	resetLogger()
+	t.Cleanup(resetLogger)
+
+	allowed := map[Layer]int{LayerHTTP: 2, LayerDB: 1}
+	logger := Init(&MockFormatter{}, LevelInfo, allowed)
+	if got := logger.AllowedLayers(); !slices.Equal(got, []Layer{LayerDB, LayerHTTP}) {
+		t.Errorf("AllowedLayers() = %v, want the priorities given to Init", got)
+	}
+	logger.SetLayerPriority(LayerDB, 5)
+	if allowed[LayerDB] != 1 {
+		t.Error("expected Init to copy the map it was given")
+	}
+
+	config := DefaultConfig()
+	config.StrictMode = true
+	config.AllowedLayers = []Layer{LayerHTTP, LayerCORE, LayerDB}
+	strict := New(&MockFormatter{}, LevelInfo, config)
+	if got := strict.AllowedLayers(); !slices.Equal(got, config.AllowedLayers) {
+		t.Errorf("AllowedLayers() = %v, want the order of Config.AllowedLayers", got)
+	}
+}
+
+func TestLoggerSetOutput(t *testing.T) {
+	resetLogger()
+
+	var buf bytes.Buffer
+	logger := Init(&PlainTextFormatter{}, LevelInfo, nil)
+	logger.SetOutput(&buf)
+
+	logger.Info("written to buffer")
+	logger.Debug("filtered out")
+
+	output := buf.String()
+	if !strings.Contains(output, "[INFO]") || !strings.Contains(output, "written to buffer") {
+		t.Errorf("expected info line in buffer, got %q", output)
+	}
+	if strings.Contains(output, "filtered out") {
+		t.Errorf("expected debug line to be filtered, got %q", output)
+	}
+	if strings.Count(output, "\n") != 1 {
+		t.Errorf("expected exactly one line, got %q", output)
+	}
+}
+
+func TestLoggerSetOutputsSplitsByLevel(t *testing.T) {
+	resetLogger()
+
+	var out, errOut bytes.Buffer
+	logger := Init(&MockFormatter{}, LevelInfo, nil)
+	logger.SetOutputs(&out, &errOut)
+
+	logger.Debug("debug line")
+	logger.Info("info line")
+	logger.Warn("warn line")
+	logger.Error("error line")
+
+	if out.String() != "info line\n" {
+		t.Errorf("expected only info on out, got %q", out.String())
+	}
+	if errOut.String() != "warn line\nerror line\n" {
+		t.Errorf("expected warn and error on errOut, got %q", errOut.String())
+	}
+}
+
+func TestLoggerSetLevel(t *testing.T) {
+	resetLogger()
+
+	mock := &MockFormatter{}
+	logger := Init(mock, LevelInfo, nil)
+	logger.SetOutput(&bytes.Buffer{})
+
+	logger.Debug("hidden")
+	if mock.LastFormatted != "" {
+		t.Errorf("expected debug to be filtered at LevelInfo, got %q", mock.LastFormatted)
+	}
+
+	logger.SetLevel(LevelDebug)
+	if logger.GetLevel() != LevelDebug {
+		t.Errorf("expected LevelDebug, got %s", logger.GetLevel())
+	}
+
+	logger.Debug("visible")
+	if mock.LastFormatted != "visible" {
+		t.Errorf("expected debug to log after SetLevel, got %q", mock.LastFormatted)
+	}
+}
+
+func TestLoggerSetLevelConcurrent(t *testing.T) {
+	resetLogger()
+
+	logger := Init(&PlainTextFormatter{}, LevelInfo, nil)
+	logger.SetOutput(&bytes.Buffer{})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(2)
+		go func(n int) {
+			defer wg.Done()
+			if n%2 == 0 {
+				logger.SetLevel(LevelDebug)
+			} else {
+				logger.SetLevel(LevelInfo)
+			}
+		}(i)
+		go func() {
+			defer wg.Done()
+			logger.Debug("concurrent")
+		}()
+	}
+	wg.Wait()
+}
+
+func TestLoggerIncludeCaller(t *testing.T) {
+	resetLogger()
+
+	config := DefaultConfig()
+	config.IncludeCaller = true
+
+	var buf bytes.Buffer
+	logger := InitWithConfig(&PlainTextFormatter{}, LevelInfo, config)
+	logger.SetOutput(&buf)
+
+	_, _, line, _ := runtime.Caller(0)
+	logger.Info("with caller") // must stay on the line after runtime.Caller
+	logger.WithField("k", "v").Info("entry with caller")
+
+	want := fmt.Sprintf("[logger_test.go:%d] with caller", line+1)
+	if !strings.Contains(buf.String(), want) {
+		t.Errorf("expected %q in output, got %q", want, buf.String())
+	}
+	wantEntry := fmt.Sprintf("[logger_test.go:%d] entry with caller", line+2)
+	if !strings.Contains(buf.String(), wantEntry) {
+		t.Errorf("expected %q in output, got %q", wantEntry, buf.String())
+	}
+}
+
+func TestLoggerIncludeCallerWindowsPath(t *testing.T) {
+	config := DefaultConfig()
+	config.IncludeCaller = true
+	logger := New(&PlainTextFormatter{}, LevelInfo, config)
+	logger.SetOutput(&bytes.Buffer{})
+	sink := &MemorySink{}
+	logger.AddEntrySink(sink)
+
+	logger.emit(LogEntry{Level: LevelInfo, Layer: LayerHTTP, Message: "from windows"}, `C:\work\app\api\handlers.go`, 42)
+
+	entry, _ := sink.LastEntry()
+	if entry.Caller() != "handlers.go:42" {
+		t.Errorf("Caller() = %q, want %q", entry.Caller(), "handlers.go:42")
+	}
+}
+
+func TestSlashPath(t *testing.T) {
+	tests := map[string]string{
+		`C:\work\app\main.go`: "C:/work/app/main.go",
+		"/home/app/main.go":   "/home/app/main.go",
+		"main.go":             "main.go",
+	}
+	for in, want := range tests {
+		if got := slashPath(in); got != want {
+			t.Errorf("slashPath(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestLoggerInfow(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(&PlainTextFormatter{}, LevelDebug, DefaultConfig())
+	logger.SetOutputs(&buf, &buf)
+
+	logger.Infow("request processed", "userID", 456, "ms", 12)
+	logger.Warnw("odd", "retry")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %q", buf.String())
+	}
+	if !strings.HasSuffix(lines[0], "request processed userID=456 ms=12") {
+		t.Errorf("unexpected line %q", lines[0])
+	}
+	if !strings.HasSuffix(lines[1], "odd retry="+MissingValue) {
+		t.Errorf("unexpected line %q", lines[1])
+	}
+}
+
+func TestLoggerInfoln(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(&MockFormatter{}, LevelTrace, DefaultConfig())
+	logger.SetOutputs(&buf, &buf)
+
+	logger.Infoln("cache miss for", "user:42", "after", 3*time.Millisecond)
+	logger.Errorln(errors.New("boom"), 7, true)
+	logger.WithField("k", "v").Debugln("a", "b")
+	logger.Traceln()
+
+	want := "cache miss for user:42 after 3ms\nboom 7 true\na b\n\n"
+	if buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+}
+
+func TestLoggerLogPrebuiltEntry(t *testing.T) {
+	config := DefaultConfig()
+	config.IncludeCaller = true
+
+	var buf bytes.Buffer
+	logger := New(&PlainTextFormatter{}, LevelInfo, config)
+	logger.SetOutput(&buf)
+
+	ts := time.Date(2025, 9, 29, 12, 0, 0, 0, time.UTC)
+	entry := LogEntry{Level: LevelInfo, Layer: LayerDB, Message: "replayed", Timestamp: ts}
+	entry.AddMetadata("rows", 3)
+
+	_, _, line, _ := runtime.Caller(0)
+	logger.Log(entry) // must stay on the line after runtime.Caller
+
+	want := fmt.Sprintf("[INFO] [DB] [%s] [logger_test.go:%d] replayed rows=3", ts.Format(TimeFormat), line+1)
+	if got := strings.TrimSpace(buf.String()); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestLoggerLogResolvesEmptyLayerAndFilters(t *testing.T) {
+	var got []LogEntry
+
+	logger := New(&MockFormatter{}, LevelInfo, DefaultConfig())
+	logger.SetOutput(&bytes.Buffer{})
+	logger.AddHook(func(e LogEntry) { got = append(got, e) })
+
+	logger.Log(LogEntry{Level: LevelDebug, Message: "filtered"})
+	logger.Log(LogEntry{Level: LevelWarn, Message: "resolved"})
+
+	if len(got) != 1 {
+		t.Fatalf("expected 1 entry, got %d", len(got))
+	}
+	want := Layer(resolveLayer(logger, getCurrentPackage(1)))
+	if got[0].Layer != want {
+		t.Errorf("Layer = %q, want the calling package's layer %q", got[0].Layer, want)
+	}
+	if got[0].Timestamp.IsZero() {
+		t.Error("expected a zero Timestamp to be filled in")
+	}
+}
+
+func TestLoggerCallerOffByDefault(t *testing.T) {
+	resetLogger()
+
+	var buf bytes.Buffer
+	logger := Init(&PlainTextFormatter{}, LevelInfo, nil)
+	logger.SetOutput(&buf)
+
+	logger.Info("no caller")
+
+	if strings.Contains(buf.String(), ".go:") {
+		t.Errorf("expected no caller without IncludeCaller, got %q", buf.String())
+	}
+}
+
+func TestLoggerCaptureStackOnError(t *testing.T) {
+	resetLogger()
+
+	config := DefaultConfig()
+	config.CaptureStackOnError = true
+
+	var out, errOut bytes.Buffer
+	logger := InitWithConfig(&PlainTextFormatter{}, LevelInfo, config)
+	logger.SetOutputs(&out, &errOut)
+
+	logger.Info("no stack here")
+	logger.Error("boom")
+
+	if strings.Contains(out.String(), "logger_test.go") {
+		t.Errorf("expected no stack for Info, got %q", out.String())
+	}
+
+	lines := strings.Split(errOut.String(), "\n")
+	if len(lines) < 3 {
+		t.Fatalf("expected stack lines after error, got %q", errOut.String())
+	}
+	if want := "    github.com/cheezecakee/logr.TestLoggerCaptureStackOnError()"; lines[1] != want {
+		t.Errorf("expected stack to start at the caller %q, got %q", want, lines[1])
+	}
+	if !strings.HasPrefix(lines[2], "    \t") || !strings.Contains(lines[2], "logger_test.go:") {
+		t.Errorf("expected indented file:line, got %q", lines[2])
+	}
+}
+
+func TestNewIsIndependentOfGlobal(t *testing.T) {
+	resetLogger()
+
+	var globalBuf, auditBuf bytes.Buffer
+	global := Init(&PlainTextFormatter{}, LevelWarn, nil)
+	global.SetOutput(&globalBuf)
+
+	audit := New(JSONFormatter{}, LevelDebug, DefaultConfig())
+	audit.SetOutput(&auditBuf)
+
+	if audit == global || Get() != global {
+		t.Fatal("expected New to return a logger separate from the global one")
+	}
+
+	audit.Debug("audit event")
+	global.Debug("app debug")
+
+	if !strings.Contains(auditBuf.String(), `"message":"audit event"`) {
+		t.Errorf("expected JSON audit line, got %q", auditBuf.String())
+	}
+	if globalBuf.Len() != 0 {
+		t.Errorf("expected global logger to filter debug, got %q", globalBuf.String())
+	}
+}
+
+func TestResetAllowsReinit(t *testing.T) {
+	resetLogger()
+
+	first := Init(&MockFormatter{}, LevelInfo, nil)
+	if err := Reset(); err != nil {
+		t.Fatalf("Reset() error = %v", err)
+	}
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("expected Get to panic after Reset")
+			}
+		}()
+		Get()
+	}()
+
+	second := Init(&MockFormatter{}, LevelDebug, nil)
+	if second == first {
+		t.Fatal("expected Init after Reset to build a new logger")
+	}
+	if Get().GetLevel() != LevelDebug {
+		t.Errorf("expected new logger level Debug, got %v", Get().GetLevel())
+	}
+	if !first.closed.Load() {
+		t.Error("expected Reset to close the previous logger")
+	}
+}
+
+func TestGetOrDefaultBeforeInit(t *testing.T) {
+	resetLogger()
+	t.Cleanup(resetLogger)
+
+	fallback := GetOrDefault()
+	if fallback == nil {
+		t.Fatal("expected a fallback logger before Init")
+	}
+	if GetOrDefault() != fallback {
+		t.Error("expected the same fallback logger on every call")
+	}
+	if fallback.GetLevel() != LevelInfo {
+		t.Errorf("expected fallback level Info, got %v", fallback.GetLevel())
+	}
+
+	global := Init(&MockFormatter{}, LevelDebug, nil)
+	if global == fallback {
+		t.Fatal("expected Init to build its own logger, not reuse the fallback")
+	}
+	if GetOrDefault() != global {
+		t.Error("expected GetOrDefault to return the global logger after Init")
+	}
+}
+
+func TestNopDiscardsEverything(t *testing.T) {
+	var buf bytes.Buffer
+	var hooked bool
+
+	logger := Nop()
+	logger.SetLevel(LevelTrace)
+	logger.SetOutput(&buf)
+	logger.AddSink(&buf)
+	logger.AddHook(func(LogEntry) { hooked = true })
+
+	logger.Trace("trace")
+	logger.Error("error")
+	logger.WithField("key", "value").Warnf("warn %d", 1)
+	logger.InfoCtx(context.Background(), "ctx")
+
+	if buf.Len() != 0 {
+		t.Errorf("expected no output from Nop logger, got %q", buf.String())
+	}
+	if hooked {
+		t.Error("expected hooks not to run on a Nop logger")
+	}
+	if err := logger.Close(); err != nil {
+		t.Errorf("Close() error = %v", err)
+	}
+}
+
+func TestNewInvalidConfigPanics(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("Expected New with invalid config to panic")
+		}
+	}()
+
+	New(&PlainTextFormatter{}, LevelInfo, Config{DefaultDepth: -1})
+}
+
+func TestCloneIsIndependent(t *testing.T) {
+	var buf bytes.Buffer
+	parent := New(&PlainTextFormatter{}, LevelWarn, DefaultConfig())
+	parent.SetOutput(&buf)
+	if err := parent.setLayerForPath("myapp/db", "DATABASE"); err != nil {
+		t.Fatal(err)
+	}
+	parent.Warn("warm the cache")
+
+	var parentHooks, childHooks int
+	parent.AddHook(func(LogEntry) { parentHooks++ })
+
+	child := parent.Clone()
+	child.SetLevel(LevelDebug)
+	child.AddHook(func(LogEntry) { childHooks++ })
+	if err := child.setLayerForPath("myapp/db", "STORAGE"); err != nil {
+		t.Fatal(err)
+	}
+
+	if parent.GetLevel() != LevelWarn {
+		t.Errorf("parent level = %v, want Warn", parent.GetLevel())
+	}
+	if got := resolveLayer(parent, "myapp/db/postgres"); got != "DATABASE" {
+		t.Errorf("parent layer = %q, want DATABASE", got)
+	}
+	if got := resolveLayer(child, "myapp/db/postgres"); got != "STORAGE" {
+		t.Errorf("child layer = %q, want STORAGE", got)
+	}
+
+	buf.Reset()
+	child.Debug("from child")
+	if !strings.Contains(buf.String(), "from child") {
+		t.Errorf("expected the clone to share the parent's output, got %q", buf.String())
+	}
+	if parentHooks != 1 || childHooks != 1 {
+		t.Errorf("hooks ran parent=%d child=%d, want 1 and 1", parentHooks, childHooks)
+	}
+
+	parent.Warn("from parent")
+	if parentHooks != 2 || childHooks != 1 {
+		t.Errorf("hooks ran parent=%d child=%d, want 2 and 1", parentHooks, childHooks)
+	}
+}
+
+func TestCloneStartsWithFreshCache(t *testing.T) {
+	parent := New(&PlainTextFormatter{}, LevelInfo, DefaultConfig())
+	parent.SetOutput(&bytes.Buffer{})
+	parent.Info("warm the cache")
+
+	child := parent.Clone()
+	if size, _, _ := child.CacheStats(); size != 0 {
+		t.Errorf("clone cache size = %d, want 0", size)
+	}
+	if size, _, _ := parent.CacheStats(); size == 0 {
+		t.Error("expected the parent cache to be untouched")
+	}
+}
+
+func TestCloseCloneLeavesParentOpen(t *testing.T) {
+	var buf bytes.Buffer
+	parent := New(&PlainTextFormatter{}, LevelInfo, DefaultConfig())
+	parent.SetOutput(&buf)
+
+	if err := parent.Clone().Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	parent.Info("still open")
+	if !strings.Contains(buf.String(), "still open") {
+		t.Errorf("expected the parent to keep logging, got %q", buf.String())
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+func TestLoggerAddSink(t *testing.T) {
+	resetLogger()
+
+	var out, sink1, sink2 bytes.Buffer
+	logger := Init(&MockFormatter{}, LevelInfo, nil)
+	logger.SetOutput(&out)
+	logger.AddSink(&sink1)
+	logger.AddSink(&sink2)
+
+	logger.Info("fan out")
+	logger.Error("fan out error")
+
+	want := "fan out\nfan out error\n"
+	for name, buf := range map[string]*bytes.Buffer{"out": &out, "sink1": &sink1, "sink2": &sink2} {
+		if buf.String() != want {
+			t.Errorf("%s: expected %q, got %q", name, want, buf.String())
+		}
+	}
+}
+
+// levelRecorder is a LevelWriter that records the level of each line.
+type levelRecorder struct {
+	levels []Level
+}
+
+func (r *levelRecorder) Write(p []byte) (int, error) {
+	return r.WriteLevel(LevelInfo, p)
+}
+
+func (r *levelRecorder) WriteLevel(level Level, p []byte) (int, error) {
+	r.levels = append(r.levels, level)
+	return len(p), nil
+}
+
+func TestLoggerLevelWriterSink(t *testing.T) {
+	var out bytes.Buffer
+	rec := &levelRecorder{}
+
+	logger := New(&MockFormatter{}, LevelDebug, DefaultConfig())
+	logger.SetOutput(&out)
+	logger.AddSink(rec)
+
+	logger.Debug("debug")
+	logger.Warn("warn")
+	logger.Error("error")
+
+	want := []Level{LevelDebug, LevelWarn, LevelError}
+	if !slices.Equal(rec.levels, want) {
+		t.Errorf("sink saw levels %v, want %v", rec.levels, want)
+	}
+}
+
+func TestLoggerFailingSinkDoesNotBlockOthers(t *testing.T) {
+	resetLogger()
+
+	var out, sink bytes.Buffer
+	var errs []error
+	logger := Init(&MockFormatter{}, LevelInfo, nil)
+	logger.SetOutput(&out)
+	logger.AddSink(failingWriter{})
+	logger.AddSink(&sink)
+	logger.SetErrorHandler(func(err error) {
+		errs = append(errs, err)
+	})
+
+	logger.Info("still delivered")
+
+	if sink.String() != "still delivered\n" {
+		t.Errorf("expected healthy sink to receive line, got %q", sink.String())
+	}
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "disk full") {
+		t.Errorf("expected one reported write error, got %v", errs)
+	}
+}
+
+func TestLoggerSetLevelForLayer(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(&MockFormatter{}, LevelInfo, DefaultConfig())
+	logger.SetOutput(&buf)
+
+	// Route this package's logs to the DB layer
+	logger.registry[getCurrentPackage(1)] = &packageConfig{explicitLayer: stringPtr("DB")}
+
+	// DB is noisy: only Warn and above
+	logger.SetLevelForLayer(LayerDB, LevelWarn)
+	logger.Info("db info")
+	logger.Warn("db warn")
+
+	if buf.String() != "db warn\n" {
+		t.Errorf("expected only warn for DB layer, got %q", buf.String())
+	}
+
+	// Other layers keep the global level
+	logger.SetLevelForLayer(LayerHTTP, LevelError)
+	logger.SetLevelForLayer(LayerDB, LevelDebug)
+	logger.SetLevel(LevelError)
+	buf.Reset()
+
+	logger.Debug("db debug")
+	if buf.String() != "db debug\n" {
+		t.Errorf("expected layer level to override a stricter global level, got %q", buf.String())
+	}
+}
+
+func TestLoggerEnabled(t *testing.T) {
+	logger := New(&MockFormatter{}, LevelInfo, DefaultConfig())
+
+	if logger.Enabled(LevelDebug) {
+		t.Error("expected Debug to be disabled at Info level")
+	}
+	if !logger.Enabled(LevelInfo) || !logger.Enabled(LevelError) {
+		t.Error("expected Info and Error to be enabled at Info level")
+	}
+	if logger.Enabled(LevelTest) {
+		t.Error("expected Test to be disabled without TestMode")
+	}
+
+	logger.Close()
+	if logger.Enabled(LevelError) {
+		t.Error("expected nothing to be enabled after Close")
+	}
+	if Nop().Enabled(LevelError) {
+		t.Error("expected nothing to be enabled on a Nop logger")
+	}
+}
+
+func TestLoggerEnabledForLayer(t *testing.T) {
+	logger := New(&MockFormatter{}, LevelInfo, DefaultConfig())
+	logger.registry[getCurrentPackage(1)] = &packageConfig{explicitLayer: stringPtr("DB")}
+
+	logger.SetLevelForLayer(LayerDB, LevelWarn)
+	if logger.Enabled(LevelInfo) {
+		t.Error("expected Info to be disabled for the caller's DB layer")
+	}
+	if !logger.Enabled(LevelWarn) {
+		t.Error("expected Warn to be enabled for the caller's DB layer")
+	}
+
+	logger.SetLevelForLayer(LayerHTTP, LevelDebug)
+	if !logger.WithLayer(LayerHTTP).Enabled(LevelDebug) {
+		t.Error("expected Debug to be enabled for an Entry with the HTTP layer")
+	}
+	if logger.WithField("k", "v").Enabled(LevelDebug) {
+		t.Error("expected an Entry without a layer to use the caller's layer")
+	}
+}
+
+func TestLoggerLayerFilters(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(&MockFormatter{}, LevelInfo, DefaultConfig())
+	logger.SetOutput(&buf)
+
+	logAll := func() string {
+		buf.Reset()
+		logger.WithLayer(LayerDB).Info("db")
+		logger.WithLayer(LayerHTTP).Info("http")
+		logger.WithLayer(LayerCORE).Error("core")
+		return buf.String()
+	}
+
+	logger.OnlyLayers(LayerDB)
+	if got := logAll(); got != "db\n" {
+		t.Errorf("OnlyLayers(DB) wrote %q, want only the DB line", got)
+	}
+
+	logger.OnlyLayers(LayerDB, LayerCORE)
+	logger.ExceptLayers(LayerDB)
+	if got := logAll(); got != "core\n" {
+		t.Errorf("OnlyLayers(DB, CORE) with ExceptLayers(DB) wrote %q, want only the CORE line", got)
+	}
+
+	logger.OnlyLayers()
+	if got := logAll(); got != "http\ncore\n" {
+		t.Errorf("ExceptLayers(DB) wrote %q, want HTTP and CORE", got)
+	}
+
+	logger.ExceptLayers(LayerHTTP)
+	logger.ClearLayerFilters()
+	if got := logAll(); got != "db\nhttp\ncore\n" {
+		t.Errorf("after ClearLayerFilters wrote %q, want every line", got)
+	}
+}
+
+func TestLoggerLayerFiltersResolvedLayer(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(&MockFormatter{}, LevelInfo, DefaultConfig())
+	logger.SetOutput(&buf)
+	logger.registry[getCurrentPackage(1)] = &packageConfig{explicitLayer: stringPtr("DB")}
+
+	logger.ExceptLayers(LayerDB)
+	logger.Info("resolved to DB")
+	if buf.Len() != 0 {
+		t.Errorf("expected the resolved DB layer to be filtered, got %q", buf.String())
+	}
+}
+
+func TestLoggerReportsFormatErrors(t *testing.T) {
+	var buf bytes.Buffer
+	var errs []error
+	logger := New(JSONFormatter{}, LevelInfo, DefaultConfig())
+	logger.SetOutput(&buf)
+	logger.SetErrorHandler(func(err error) {
+		errs = append(errs, err)
+	})
+
+	logger.WithField("callback", func() {}).Info("unencodable")
+
+	if buf.Len() != 0 {
+		t.Errorf("expected nothing written for a failed entry, got %q", buf.String())
+	}
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "format entry") {
+		t.Errorf("expected a format error to be reported, got %v", errs)
+	}
+}
+
+func TestSetErrorHandlerNilRestoresDefault(t *testing.T) {
+	logger := New(&MockFormatter{}, LevelInfo, DefaultConfig())
+	logger.SetErrorHandler(func(error) {})
+	logger.SetErrorHandler(nil)
+
+	if logger.errorHandler == nil {
+		t.Error("expected default error handler after SetErrorHandler(nil)")
+	}
+}
+
+// closeRecorder is a writer that records whether it was closed.
+type closeRecorder struct {
+	bytes.Buffer
+	closes int
+}
+
+func (c *closeRecorder) Close() error {
+	c.closes++
+	return nil
+}
+
+func TestLoggerCloseLeavesWritersOpen(t *testing.T) {
+	out := &closeRecorder{}
+	sink := &closeRecorder{}
+
+	logger := New(&MockFormatter{}, LevelInfo, DefaultConfig())
+	logger.SetOutput(out)
+	logger.AddSink(sink)
+
+	if err := logger.Close(); err != nil {
+		t.Errorf("Close() error = %v", err)
+	}
+	if err := logger.Close(); err != nil {
+		t.Errorf("expected second Close to be a no-op, got %v", err)
+	}
+
+	if out.closes != 0 || sink.closes != 0 {
+		t.Errorf("expected borrowed writers left open, got %d and %d closes", out.closes, sink.closes)
+	}
+}
+
+func TestLoggerLogAfterCloseIsNoOp(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(&MockFormatter{}, LevelInfo, DefaultConfig())
+	logger.SetOutput(&buf)
+
+	logger.Close()
+	logger.Info("after close")
+	logger.WithField("k", "v").Error("after close")
+
+	if buf.Len() != 0 {
+		t.Errorf("expected no output after Close, got %q", buf.String())
+	}
+}
+
 // Test that SetLayerForPackage stores correctly
 func TestSetLayerForPackage(t *testing.T) {
 	// Reset logger
@@ -198,6 +1123,90 @@ func TestSetDepthNegativePanics(t *testing.T) {
 	logger.SetDepth(-1)
 }
 
+func TestSetDepthSafeRejectsNegative(t *testing.T) {
+	logger := New(&PlainTextFormatter{}, LevelInfo, DefaultConfig())
+
+	err := logger.SetDepthSafe(-1)
+	if !errors.Is(err, ErrInvalidDepth) {
+		t.Fatalf("SetDepthSafe(-1) error = %v, want ErrInvalidDepth", err)
+	}
+	if !strings.Contains(err.Error(), "-1") {
+		t.Errorf("expected error to mention the depth, got %q", err)
+	}
+
+	if err := logger.SetDepthSafe(1); err != nil {
+		t.Errorf("SetDepthSafe(1) error = %v", err)
+	}
+}
+
+func TestSetLayerSafeUnknownLayer(t *testing.T) {
+	logger := New(&MockFormatter{}, LevelInfo, DefaultConfig())
+
+	err := logger.SetLayerSafe("UNKNOWN")
+	if !errors.Is(err, ErrUnknownLayer) {
+		t.Fatalf("SetLayerSafe() error = %v, want ErrUnknownLayer", err)
+	}
+	if !strings.Contains(err.Error(), "UNKNOWN") {
+		t.Errorf("expected error to name the layer, got %q", err)
+	}
+
+	layer := logger.RegisterLayer("jobs")
+	if err := logger.SetLayerSafe(layer); err != nil {
+		t.Errorf("SetLayerSafe(registered) error = %v", err)
+	}
+	if logger.defaultLayer != layer {
+		t.Errorf("expected defaultLayer %q, got %q", layer, logger.defaultLayer)
+	}
+}
+
+func TestSetLayerForPackageStrictMode(t *testing.T) {
+	config := DefaultConfig()
+	config.StrictMode = true
+	config.AllowedLayers = []Layer{LayerHTTP, LayerDB}
+	logger := New(&PlainTextFormatter{}, LevelInfo, config)
+
+	if err := logger.setLayerForPath("myapp/db", "DB"); err != nil {
+		t.Errorf("allowed layer rejected: %v", err)
+	}
+	if got := resolveLayer(logger, "myapp/db/postgres"); got != "DB" {
+		t.Errorf("resolveLayer() = %q, want %q", got, "DB")
+	}
+
+	err := logger.setLayerForPath("myapp/cache", "Cache")
+	if !errors.Is(err, ErrUnknownLayer) {
+		t.Fatalf("setLayerForPath(disallowed) error = %v, want ErrUnknownLayer", err)
+	}
+	if got := resolveLayer(logger, "myapp/cache"); got == "Cache" {
+		t.Error("disallowed layer was stored anyway")
+	}
+
+	if err := logger.SetLayerForPackageSafe("Cache"); !errors.Is(err, ErrUnknownLayer) {
+		t.Errorf("SetLayerForPackageSafe(disallowed) error = %v, want ErrUnknownLayer", err)
+	}
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("expected SetLayerForPackage to panic for a disallowed layer")
+			}
+		}()
+		logger.SetLayerForPackage("Cache")
+	}()
+
+	// Registering the layer allows it
+	logger.RegisterLayer("cache")
+	if err := logger.setLayerForPath("myapp/cache", "CACHE"); err != nil {
+		t.Errorf("registered layer rejected: %v", err)
+	}
+}
+
+func TestSetLayerForPackageAnyLayerWithoutStrictMode(t *testing.T) {
+	logger := New(&PlainTextFormatter{}, LevelInfo, DefaultConfig())
+
+	if err := logger.setLayerForPath("myapp/cache", "Anything"); err != nil {
+		t.Errorf("setLayerForPath() error = %v", err)
+	}
+}
+
 // Test concurrent access to registry
 func TestConcurrentRegistryAccess(t *testing.T) {
 	resetLogger()
@@ -242,7 +1251,7 @@ func TestCacheInvalidation(t *testing.T) {
 
 	// Manually add something to cache
 	testPkg := "myapp/test"
-	logger.layerCache[testPkg] = "old-value"
+	logger.layerCache.set(testPkg, "old-value")
 
 	// Now change the registry (simulate by directly modifying)
 	logger.registryMu.Lock()
@@ -257,12 +1266,12 @@ func TestCacheInvalidation(t *testing.T) {
 
 	// Manually test cache deletion
 	logger.registryMu.Lock()
-	delete(logger.layerCache, testPkg)
+	logger.layerCache.remove(testPkg)
 	logger.registryMu.Unlock()
 
 	// Verify cache is empty for that package
 	logger.registryMu.RLock()
-	_, exists := logger.layerCache[testPkg]
+	_, exists := logger.layerCache.peek(testPkg)
 	logger.registryMu.RUnlock()
 
 	if exists {
@@ -270,7 +1279,245 @@ func TestCacheInvalidation(t *testing.T) {
 	}
 }
 
+func TestLoggerRegistry(t *testing.T) {
+	logger := New(&MockFormatter{}, LevelInfo, DefaultConfig())
+	logger.SetLayerForPackage("Database")
+	logger.SetDepth(3)
+	depth := 1
+	logger.registry["myapp/api"] = &packageConfig{explicitDepth: &depth}
+
+	snapshot := logger.Registry()
+
+	pkg := getCurrentPackage(1)
+	want := map[string]PackageLayerInfo{
+		pkg:         {Layer: "Database", HasLayer: true, Depth: 3, HasDepth: true},
+		"myapp/api": {Depth: 1, HasDepth: true},
+	}
+	if !reflect.DeepEqual(snapshot, want) {
+		t.Errorf("Registry() = %+v, want %+v", snapshot, want)
+	}
+
+	snapshot[pkg] = PackageLayerInfo{Layer: "Changed", HasLayer: true}
+	if got := *logger.registry[pkg].explicitLayer; got != "Database" {
+		t.Errorf("changing the snapshot changed the registry: layer %q", got)
+	}
+}
+
+func TestLoggerSlowWriteDoesNotBlockSettings(t *testing.T) {
+	w := newBlockingWriter()
+	logger := New(&MockFormatter{}, LevelInfo, DefaultConfig())
+	logger.SetOutput(w)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		logger.Info("slow")
+	}()
+	<-w.started
+
+	settled := make(chan struct{})
+	go func() {
+		defer close(settled)
+		logger.SetLevel(LevelDebug)
+		_ = logger.Enabled(LevelDebug)
+	}()
+	select {
+	case <-settled:
+	case <-time.After(time.Second):
+		t.Error("SetLevel blocked behind a write in progress")
+	}
+
+	close(w.release)
+	<-done
+	if got := w.String(); got != "slow\n" {
+		t.Errorf("wrote %q, want %q", got, "slow\n")
+	}
+}
+
+func newStrictLogger(action StrictModeAction) (*Logger, *bytes.Buffer, *[]error) {
+	config := DefaultConfig()
+	config.StrictMode = true
+	config.StrictModeAction = action
+	config.AllowedLayers = []Layer{LayerHTTP}
+	logger := New(&MockFormatter{}, LevelInfo, config)
+
+	var buf bytes.Buffer
+	logger.SetOutput(&buf)
+	var errs []error
+	logger.SetErrorHandler(func(err error) { errs = append(errs, err) })
+	return logger, &buf, &errs
+}
+
+func TestStrictModeActionPanic(t *testing.T) {
+	logger, _, _ := newStrictLogger(StrictPanic)
+
+	for name, assign := range map[string]func(){
+		"SetLayer":           func() { logger.SetLayer(LayerDB) },
+		"SetLayerForPackage": func() { logger.SetLayerForPackage("DB") },
+		"WithLayer":          func() { logger.WithLayer(LayerDB).Info("query") },
+		"Log":                func() { logger.Log(LogEntry{Level: LevelInfo, Layer: LayerDB, Message: "query"}) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s: expected a panic for a disallowed layer", name)
+				}
+			}()
+			assign()
+		}()
+	}
+
+	if err := logger.SetLayerSafe(LayerDB); !errors.Is(err, ErrUnknownLayer) {
+		t.Errorf("SetLayerSafe() error = %v, want ErrUnknownLayer", err)
+	}
+	if err := logger.SetLayerForPackageSafe("DB"); !errors.Is(err, ErrUnknownLayer) {
+		t.Errorf("SetLayerForPackageSafe() error = %v, want ErrUnknownLayer", err)
+	}
+}
+
+func TestStrictModeActionError(t *testing.T) {
+	logger, buf, errs := newStrictLogger(StrictError)
+
+	logger.SetLayer(LayerDB)
+	logger.SetLayerForPackage("DB")
+	logger.WithLayer(LayerDB).Info("query")
+	logger.Log(LogEntry{Level: LevelInfo, Layer: LayerDB, Message: "query"})
+
+	if len(*errs) != 4 {
+		t.Fatalf("got %d errors, want 4: %v", len(*errs), *errs)
+	}
+	for _, err := range *errs {
+		if !errors.Is(err, ErrUnknownLayer) {
+			t.Errorf("reported %v, want ErrUnknownLayer", err)
+		}
+	}
+	if buf.Len() != 0 {
+		t.Errorf("expected the entries to be dropped, got %q", buf.String())
+	}
+	if logger.defaultLayer != "" {
+		t.Errorf("SetLayer changed the layer to %q", logger.defaultLayer)
+	}
+	if _, ok := logger.Registry()[getCurrentPackage(1)]; ok {
+		t.Error("SetLayerForPackage stored the layer")
+	}
+
+	if err := logger.SetLayerSafe(LayerDB); !errors.Is(err, ErrUnknownLayer) {
+		t.Errorf("SetLayerSafe() error = %v, want ErrUnknownLayer", err)
+	}
+}
+
+func TestStrictModeActionWarn(t *testing.T) {
+	logger, _, errs := newStrictLogger(StrictWarn)
+	var got []LogEntry
+	logger.AddHook(func(e LogEntry) { got = append(got, e) })
+
+	logger.WithLayer(LayerDB).Info("first")
+	logger.WithLayer(LayerDB).Info("second")
+	if err := logger.SetLayerForPackageSafe("DB"); err != nil {
+		t.Errorf("SetLayerForPackageSafe() error = %v, want nil", err)
+	}
+
+	if len(got) != 3 {
+		t.Fatalf("got %d entries, want a warning and 2 lines: %+v", len(got), got)
+	}
+	warning := got[0]
+	if layer, _ := warning.Metadata.GetString("layer"); warning.Level != LevelWarn || layer != "DB" {
+		t.Errorf("unexpected warning %+v", warning)
+	}
+	for _, e := range got[1:] {
+		if e.Layer != LayerUnknown {
+			t.Errorf("entry %q under %q, want the fallback layer %q", e.Message, e.Layer, LayerUnknown)
+		}
+	}
+	if layer := logger.ResolveLayerFor(getCurrentPackage(1)); layer != string(LayerUnknown) {
+		t.Errorf("package layer = %q, want the fallback layer", layer)
+	}
+	if len(*errs) != 0 {
+		t.Errorf("unexpected errors %v", *errs)
+	}
+}
+
+func TestStrictModeActionWarnCaller(t *testing.T) {
+	config := DefaultConfig()
+	config.StrictMode = true
+	config.StrictModeAction = StrictWarn
+	config.AllowedLayers = []Layer{LayerHTTP}
+	config.IncludeCaller = true
+	logger := New(&MockFormatter{}, LevelInfo, config)
+	logger.SetOutput(&bytes.Buffer{})
+	var got []LogEntry
+	logger.AddHook(func(e LogEntry) { got = append(got, e) })
+
+	line := nextLine()
+	logger.WithLayer(LayerDB).Info("query")
+
+	if len(got) != 2 {
+		t.Fatalf("got %d entries, want a warning and a line: %+v", len(got), got)
+	}
+	for _, e := range got {
+		if e.File != "logger_test.go" || e.Line != line {
+			t.Errorf("entry %q at %s:%d, want logger_test.go:%d", e.Message, e.File, e.Line, line)
+		}
+	}
+}
+
+func TestStrictModeActionDrop(t *testing.T) {
+	logger, buf, errs := newStrictLogger(StrictDrop)
+
+	logger.WithLayer(LayerDB).Info("dropped")
+	logger.Log(LogEntry{Level: LevelInfo, Layer: LayerDB, Message: "dropped"})
+	logger.WithLayer(LayerHTTP).Info("kept")
+
+	if buf.String() != "kept\n" {
+		t.Errorf("got %q, want only the allowed layer's line", buf.String())
+	}
+
+	// Allowing the layer stops the drops
+	logger.RegisterLayer("db")
+	logger.WithLayer(LayerDB).Info("allowed")
+	if !strings.HasSuffix(buf.String(), "allowed\n") {
+		t.Errorf("expected the line once the layer is allowed, got %q", buf.String())
+	}
+	if len(*errs) != 0 {
+		t.Errorf("unexpected errors %v", *errs)
+	}
+}
+
+func TestStrictModeActionValidate(t *testing.T) {
+	config := DefaultConfig()
+	config.StrictModeAction = StrictDrop + 1
+	if err := config.Validate(); err == nil {
+		t.Error("expected an error for an unknown StrictModeAction")
+	}
+}
+
 // Helper for tests
 func stringPtr(s string) *string {
 	return &s
 }
+
+func TestLoggerErrorErr(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(JSONFormatter{}, LevelInfo, DefaultConfig())
+	logger.SetOutput(&buf)
+
+	var got error
+	logger.AddHook(func(e LogEntry) { got = e.Err })
+
+	cause := errors.New("connection refused")
+	logger.WithField("host", "db1").ErrorErr("failed to connect", cause)
+
+	if got != cause {
+		t.Errorf("hook saw Err = %v, want the logged error", got)
+	}
+	line := buf.String()
+	if !strings.Contains(line, `"message":"failed to connect"`) || !strings.Contains(line, `"error":"connection refused"`) {
+		t.Errorf("expected message and error as separate fields, got %s", line)
+	}
+
+	buf.Reset()
+	logger.ErrorErr("no error", nil)
+	if strings.Contains(buf.String(), `"error"`) {
+		t.Errorf("expected no error field for a nil error, got %s", buf.String())
+	}
+}
@@ -0,0 +1,97 @@
+package logr
+
+import (
+	"strings"
+	"sync"
+)
+
+// MemorySink is a Sink that keeps every entry it receives, for asserting
+// on log output in tests without parsing formatted lines:
+//
+//	sink := &logr.MemorySink{}
+//	logger.AddEntrySink(sink)
+//	doWork(logger)
+//	if errs := sink.EntriesWithLevel(logr.LevelError); len(errs) != 1 {
+//		t.Fatalf("got %d errors, want 1", len(errs))
+//	}
+//
+// The zero value is ready to use. MemorySink is safe for concurrent use.
+type MemorySink struct {
+	mu      sync.Mutex
+	entries []LogEntry
+	lines   []string // Formatted lines, parallel to entries
+}
+
+// Write records entry and its formatted line. The entry's metadata is
+// copied so later changes to it don't show up in the recorded entry.
+func (s *MemorySink) Write(entry LogEntry, formatted string) error {
+	if entry.Metadata != nil {
+		entry.Metadata = entry.Metadata.Clone()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries = append(s.entries, entry)
+	s.lines = append(s.lines, formatted)
+	return nil
+}
+
+// Entries returns the recorded entries, oldest first.
+func (s *MemorySink) Entries() []LogEntry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]LogEntry(nil), s.entries...)
+}
+
+// LastEntry returns the most recent entry, or false if none was recorded.
+func (s *MemorySink) LastEntry() (LogEntry, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.entries) == 0 {
+		return LogEntry{}, false
+	}
+	return s.entries[len(s.entries)-1], true
+}
+
+// EntriesWithLevel returns the recorded entries at exactly level.
+func (s *MemorySink) EntriesWithLevel(level Level) []LogEntry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var matched []LogEntry
+	for _, entry := range s.entries {
+		if entry.Level == level {
+			matched = append(matched, entry)
+		}
+	}
+	return matched
+}
+
+// Contains reports whether any recorded line contains substr. It matches
+// the formatted line, so metadata written by the formatter counts too.
+func (s *MemorySink) Contains(substr string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, line := range s.lines {
+		if strings.Contains(line, substr) {
+			return true
+		}
+	}
+	return false
+}
+
+// Len returns the number of recorded entries.
+func (s *MemorySink) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.entries)
+}
+
+// Reset discards every recorded entry.
+func (s *MemorySink) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries = nil
+	s.lines = nil
+}
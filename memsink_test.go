@@ -0,0 +1,97 @@
+package logr
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+)
+
+func newMemoryTestLogger() (*Logger, *MemorySink) {
+	logger := New(&PlainTextFormatter{}, LevelDebug, DefaultConfig())
+	logger.SetOutput(&bytes.Buffer{})
+	sink := &MemorySink{}
+	logger.AddEntrySink(sink)
+	return logger, sink
+}
+
+func TestMemorySinkRecordsEntries(t *testing.T) {
+	logger, sink := newMemoryTestLogger()
+
+	if _, ok := sink.LastEntry(); ok {
+		t.Error("LastEntry() on an empty sink should report false")
+	}
+
+	logger.Info("started")
+	logger.Errorw("payment failed", "userID", 5)
+	logger.Debug("retrying")
+
+	entries := sink.Entries()
+	if len(entries) != 3 || sink.Len() != 3 {
+		t.Fatalf("got %d entries, want 3", len(entries))
+	}
+	if entries[0].Message != "started" {
+		t.Errorf("first entry = %q, want %q", entries[0].Message, "started")
+	}
+
+	last, ok := sink.LastEntry()
+	if !ok || last.Message != "retrying" {
+		t.Errorf("LastEntry() = %q, %v; want %q", last.Message, ok, "retrying")
+	}
+
+	errs := sink.EntriesWithLevel(LevelError)
+	if len(errs) != 1 {
+		t.Fatalf("EntriesWithLevel(LevelError) returned %d entries, want 1", len(errs))
+	}
+	if got, _ := errs[0].Metadata.Get("userID"); got != 5 {
+		t.Errorf("userID = %v, want 5", got)
+	}
+}
+
+func TestMemorySinkContains(t *testing.T) {
+	logger, sink := newMemoryTestLogger()
+
+	logger.Warnw("slow query", "table", "orders")
+
+	if !sink.Contains("slow query") {
+		t.Error("Contains() should match the message")
+	}
+	if !sink.Contains("table=orders") {
+		t.Error("Contains() should match formatted metadata")
+	}
+	if sink.Contains("fast query") {
+		t.Error("Contains() matched text that was never logged")
+	}
+}
+
+func TestMemorySinkReset(t *testing.T) {
+	logger, sink := newMemoryTestLogger()
+
+	logger.Info("before")
+	sink.Reset()
+	logger.Info("after")
+
+	if entries := sink.Entries(); len(entries) != 1 || entries[0].Message != "after" {
+		t.Errorf("Entries() after Reset = %v, want only %q", entries, "after")
+	}
+}
+
+func TestMemorySinkConcurrent(t *testing.T) {
+	logger, sink := newMemoryTestLogger()
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				logger.Infow("tick", "i", i)
+				sink.Contains("tick")
+			}
+		}()
+	}
+	wg.Wait()
+
+	if sink.Len() != 400 {
+		t.Errorf("Len() = %d, want 400", sink.Len())
+	}
+}
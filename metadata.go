@@ -1,7 +1,21 @@
 package logr
 
+import (
+	"fmt"
+	"reflect"
+	"slices"
+)
+
+// MissingValue is the value given to a trailing key that has no value in
+// the Infow family of methods.
+const MissingValue = "<missing>"
+
+// Metadata holds structured key-value fields for a log entry. Fields keep
+// the order in which they were first added.
 type Metadata struct {
-	Data map[string]any `json:"data"`
+	Data map[string]any
+
+	order []string // Keys in insertion order
 }
 
 func NewMetadata() *Metadata {
@@ -10,10 +24,74 @@ func NewMetadata() *Metadata {
 	}
 }
 
+// Add sets key to value. Re-adding an existing key updates the value
+// without changing its position.
 func (m *Metadata) Add(key string, value any) {
+	// A key deleted from Data directly is still in order; keeping order
+	// free of repeats is what lets keys return it as is
+	if _, exists := m.Data[key]; !exists && !slices.Contains(m.order, key) {
+		m.order = append(m.order, key)
+	}
 	m.Data[key] = value
 }
 
+// AddIf adds key=value only when cond is true. It returns m so calls can
+// be chained:
+//
+//	meta.AddIf(retry > 0, "retry", retry).AddIf(err != nil, "error", err)
+func (m *Metadata) AddIf(cond bool, key string, value any) *Metadata {
+	if cond {
+		m.Add(key, value)
+	}
+	return m
+}
+
+// AddNonZero adds key=value unless value is nil or the zero value of its
+// type, such as "", 0, false or an empty struct. Empty but non-nil slices
+// and maps are added. It returns m so calls can be chained.
+func (m *Metadata) AddNonZero(key string, value any) *Metadata {
+	if value == nil || reflect.ValueOf(value).IsZero() {
+		return m
+	}
+	m.Add(key, value)
+	return m
+}
+
+// Group returns the nested section called name, creating it if needed, so
+// related fields stay together like a slog group:
+//
+//	http := meta.Group("http")
+//	http.Add("method", "GET")
+//	http.Add("status", 200)
+//
+// JSON formatters write the section as a nested object,
+// "http":{"method":"GET","status":200}, and the plain text, logfmt and XML
+// formatters as dotted keys, http.method=GET http.status=200. Calling Group
+// again with the same name returns the same section; a field of that name
+// that isn't a group is replaced.
+func (m *Metadata) Group(name string) *Metadata {
+	if group, ok := m.Data[name].(*Metadata); ok {
+		return group
+	}
+	group := NewMetadata()
+	m.Add(name, group)
+	return group
+}
+
+// walk calls fn for every field in insertion order, descending into groups
+// made with Group, whose fields are named after the group and a dot:
+// "http.method".
+func (m *Metadata) walk(prefix string, fn func(key string, value any)) {
+	for _, key := range m.keys() {
+		value := m.Data[key]
+		if group, ok := value.(*Metadata); ok {
+			group.walk(prefix+key+".", fn)
+			continue
+		}
+		fn(prefix+key, value)
+	}
+}
+
 func (m *Metadata) Get(key string) (any, bool) {
 	value, ok := m.Data[key]
 	if ok {
@@ -21,3 +99,191 @@ func (m *Metadata) Get(key string) (any, bool) {
 	}
 	return nil, false
 }
+
+// GetString returns the value of key if it is a string. It returns "" and
+// false when key is missing or holds another type.
+func (m *Metadata) GetString(key string) (string, bool) {
+	return getAs[string](m, key)
+}
+
+// GetInt returns the value of key if it is an int. Other integer types,
+// such as int64, don't match.
+func (m *Metadata) GetInt(key string) (int, bool) {
+	return getAs[int](m, key)
+}
+
+// GetBool returns the value of key if it is a bool.
+func (m *Metadata) GetBool(key string) (bool, bool) {
+	return getAs[bool](m, key)
+}
+
+func getAs[T any](m *Metadata, key string) (T, bool) {
+	value, ok := m.Data[key].(T)
+	return value, ok
+}
+
+// MarshalJSON encodes the fields as a JSON object in insertion order, e.g.
+// {"requestID":"abc","userID":42}, with groups as nested objects.
+func (m *Metadata) MarshalJSON() ([]byte, error) {
+	var obj jsonObject
+	for _, key := range m.keys() {
+		obj.add(key, m.Data[key])
+	}
+	return obj.bytes()
+}
+
+// Clone returns a copy of m that can be modified without affecting the
+// original. Groups are copied too.
+func (m *Metadata) Clone() *Metadata {
+	c := &Metadata{
+		Data:  make(map[string]any, len(m.Data)),
+		order: make([]string, 0, len(m.order)),
+	}
+	for _, k := range m.keys() {
+		c.Add(k, cloneGroup(m.Data[k]))
+	}
+	return c
+}
+
+// clonePooled is Clone backed by the metadata pool, for copies the logger
+// owns and recycles.
+func (m *Metadata) clonePooled() *Metadata {
+	c := acquireMetadata()
+	for _, k := range m.keys() {
+		c.Add(k, cloneGroup(m.Data[k]))
+	}
+	return c
+}
+
+// cloneGroup returns a copy of value if it is a group made with Group, and
+// value itself otherwise.
+func cloneGroup(value any) any {
+	if group, ok := value.(*Metadata); ok {
+		return group.Clone()
+	}
+	return value
+}
+
+// Merge copies every field of other into m. Fields in other win on key
+// collisions; fields new to m are appended in other's order. Groups are
+// copied, as in Clone, so m and other don't share them.
+func (m *Metadata) Merge(other *Metadata) {
+	if other == nil {
+		return
+	}
+	for _, key := range other.keys() {
+		m.Add(key, cloneGroup(other.Data[key]))
+	}
+}
+
+// resolveLazy returns meta with every func() any value replaced by its
+// result, so a field that is expensive to compute is only built for lines
+// that are written:
+//
+//	logger.WithField("dump", func() any { return expensiveSerialize() })
+//
+// Only top-level values are evaluated. meta itself is never modified; a
+// copy is made only when it holds a lazy value. A panicking function is
+// passed to report and logged as "<panic: ...>".
+func resolveLazy(meta *Metadata, report func(error)) *Metadata {
+	if meta == nil {
+		return nil
+	}
+
+	out := meta
+	for key, value := range meta.Data {
+		fn, ok := value.(func() any)
+		if !ok {
+			continue
+		}
+		if out == meta {
+			out = meta.Clone()
+		}
+		out.Data[key] = callLazy(key, fn, report)
+	}
+	return out
+}
+
+func callLazy(key string, fn func() any, report func(error)) (value any) {
+	defer func() {
+		if r := recover(); r != nil {
+			report(fmt.Errorf("logr: lazy field %q panicked: %v", key, r))
+			value = fmt.Sprintf("<panic: %v>", r)
+		}
+	}()
+	return fn()
+}
+
+// metadataFromPairs builds metadata from alternating keys and values.
+// Keys that aren't strings are converted with fmt.Sprint, and a dangling
+// key gets MissingValue. It returns nil when there are no pairs.
+func metadataFromPairs(keysAndValues []any) *Metadata {
+	if len(keysAndValues) == 0 {
+		return nil
+	}
+
+	meta := acquireMetadata()
+	for i := 0; i < len(keysAndValues); i += 2 {
+		key, ok := keysAndValues[i].(string)
+		if !ok {
+			key = fmt.Sprint(keysAndValues[i])
+		}
+		if i+1 < len(keysAndValues) {
+			meta.Add(key, keysAndValues[i+1])
+		} else {
+			meta.Add(key, MissingValue)
+		}
+	}
+	return meta
+}
+
+// keys returns the metadata keys in insertion order. Keys written to Data
+// directly, bypassing Add, follow in sorted order so output stays
+// deterministic. The result may be m's own order slice and must not be
+// modified.
+func (m *Metadata) keys() []string {
+	if m.inOrder() {
+		return m.order
+	}
+
+	keys := make([]string, 0, len(m.Data))
+	seen := make(map[string]struct{}, len(m.order))
+	for _, key := range m.order {
+		if _, dup := seen[key]; dup {
+			continue
+		}
+		if _, ok := m.Data[key]; ok {
+			keys = append(keys, key)
+			seen[key] = struct{}{}
+		}
+	}
+
+	if len(keys) == len(m.Data) {
+		return keys
+	}
+
+	var extra []string
+	for key := range m.Data {
+		if _, ok := seen[key]; !ok {
+			extra = append(extra, key)
+		}
+	}
+	slices.Sort(extra)
+	return append(keys, extra...)
+}
+
+// inOrder reports whether order lists exactly the keys in Data, as it does
+// unless Data was written to directly. Add never repeats a key in order,
+// so matching lengths and every key being present is enough. It runs for
+// every formatted entry, so it must not allocate.
+func (m *Metadata) inOrder() bool {
+	if len(m.order) != len(m.Data) {
+		return false
+	}
+	for _, key := range m.order {
+		if _, ok := m.Data[key]; !ok {
+			return false
+		}
+	}
+	return true
+}
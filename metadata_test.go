@@ -0,0 +1,340 @@
+package logr
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestMetadataPreservesInsertionOrder(t *testing.T) {
+	meta := NewMetadata()
+	meta.Add("c", 1)
+	meta.Add("a", 2)
+	meta.Add("b", 3)
+
+	if got, want := meta.keys(), []string{"c", "a", "b"}; !slices.Equal(got, want) {
+		t.Errorf("keys() = %v, want %v", got, want)
+	}
+}
+
+func TestMetadataReAddKeepsPosition(t *testing.T) {
+	meta := NewMetadata()
+	meta.Add("first", 1)
+	meta.Add("second", 2)
+	meta.Add("first", 10)
+
+	if got, want := meta.keys(), []string{"first", "second"}; !slices.Equal(got, want) {
+		t.Errorf("keys() = %v, want %v", got, want)
+	}
+
+	value, ok := meta.Get("first")
+	if !ok || value != 10 {
+		t.Errorf("Get(first) = %v, %v; want 10, true", value, ok)
+	}
+}
+
+func TestMetadataDirectDataWritesStillListed(t *testing.T) {
+	meta := NewMetadata()
+	meta.Add("added", 1)
+	meta.Data["zdirect"] = 2
+	meta.Data["direct"] = 3
+
+	if got, want := meta.keys(), []string{"added", "direct", "zdirect"}; !slices.Equal(got, want) {
+		t.Errorf("keys() = %v, want %v", got, want)
+	}
+}
+
+func TestMetadataDirectDeleteAndWrite(t *testing.T) {
+	meta := NewMetadata()
+	meta.Add("a", 1)
+	meta.Add("b", 2)
+	delete(meta.Data, "a")
+	meta.Data["c"] = 3 // Same number of keys as order, but not the same keys
+
+	if got, want := meta.keys(), []string{"b", "c"}; !slices.Equal(got, want) {
+		t.Errorf("keys() = %v, want %v", got, want)
+	}
+}
+
+func TestMetadataReAddAfterDirectDelete(t *testing.T) {
+	meta := NewMetadata()
+	meta.Add("a", 1)
+	meta.Add("b", 2)
+	delete(meta.Data, "a")
+	meta.Add("a", 3)
+	meta.Data["x"] = 4
+
+	if got, want := meta.keys(), []string{"a", "b", "x"}; !slices.Equal(got, want) {
+		t.Errorf("keys() = %v, want %v", got, want)
+	}
+}
+
+func TestMetadataKeysDoesNotAllocate(t *testing.T) {
+	meta := NewMetadata()
+	meta.Add("userID", 42)
+	meta.Add("path", "/api")
+
+	if allocs := testing.AllocsPerRun(100, func() { meta.keys() }); allocs != 0 {
+		t.Errorf("keys() made %v allocations, want 0", allocs)
+	}
+}
+
+func TestMetadataAddIf(t *testing.T) {
+	meta := NewMetadata()
+	got := meta.AddIf(true, "kept", 1).AddIf(false, "skipped", 2)
+
+	if got != meta {
+		t.Error("expected AddIf to return the receiver")
+	}
+	if got, want := meta.keys(), []string{"kept"}; !slices.Equal(got, want) {
+		t.Errorf("keys() = %v, want %v", got, want)
+	}
+}
+
+func TestMetadataAddNonZero(t *testing.T) {
+	var nilErr error
+	meta := NewMetadata().
+		AddNonZero("name", "api").
+		AddNonZero("empty", "").
+		AddNonZero("count", 0).
+		AddNonZero("ok", false).
+		AddNonZero("nil", nil).
+		AddNonZero("nilErr", nilErr).
+		AddNonZero("nilPtr", (*int)(nil)).
+		AddNonZero("ids", []int{}).
+		AddNonZero("retries", 3)
+
+	if got, want := meta.keys(), []string{"name", "ids", "retries"}; !slices.Equal(got, want) {
+		t.Errorf("keys() = %v, want %v", got, want)
+	}
+}
+
+func TestMetadataTypedGetters(t *testing.T) {
+	meta := NewMetadata()
+	meta.Add("name", "api")
+	meta.Add("count", 3)
+	meta.Add("count64", int64(3))
+	meta.Add("ok", true)
+
+	if got, ok := meta.GetString("name"); !ok || got != "api" {
+		t.Errorf("GetString(name) = %q, %v, want api, true", got, ok)
+	}
+	if got, ok := meta.GetInt("count"); !ok || got != 3 {
+		t.Errorf("GetInt(count) = %d, %v, want 3, true", got, ok)
+	}
+	if got, ok := meta.GetBool("ok"); !ok || !got {
+		t.Errorf("GetBool(ok) = %v, %v, want true, true", got, ok)
+	}
+
+	// Type mismatches and missing keys return the zero value and false
+	if got, ok := meta.GetString("count"); ok || got != "" {
+		t.Errorf("GetString(count) = %q, %v, want \"\", false", got, ok)
+	}
+	if got, ok := meta.GetInt("count64"); ok || got != 0 {
+		t.Errorf("GetInt(count64) = %d, %v, want 0, false", got, ok)
+	}
+	if got, ok := meta.GetBool("name"); ok || got {
+		t.Errorf("GetBool(name) = %v, %v, want false, false", got, ok)
+	}
+	if _, ok := meta.GetString("missing"); ok {
+		t.Error("GetString(missing) reported a value")
+	}
+}
+
+func TestMetadataMerge(t *testing.T) {
+	request := NewMetadata()
+	request.Add("requestID", "abc")
+	request.Add("user", "alice")
+
+	call := NewMetadata()
+	call.Add("user", "bob")
+	call.Add("attempt", 2)
+
+	request.Merge(call)
+
+	if got, want := request.keys(), []string{"requestID", "user", "attempt"}; !slices.Equal(got, want) {
+		t.Errorf("keys() = %v, want %v", got, want)
+	}
+	if user, _ := request.Get("user"); user != "bob" {
+		t.Errorf("expected other to win on collision, got %v", user)
+	}
+
+	request.Merge(nil)
+	if len(request.Data) != 3 {
+		t.Errorf("expected Merge(nil) to be a no-op, got %v", request.Data)
+	}
+}
+
+func TestMetadataMergeCopiesGroups(t *testing.T) {
+	other := NewMetadata()
+	other.Group("http").Add("method", "GET")
+
+	m := NewMetadata()
+	m.Merge(other)
+	m.Group("http").Add("status", 200)
+
+	if _, ok := other.Group("http").Get("status"); ok {
+		t.Error("expected a field added to the merged group to stay out of other")
+	}
+	if method, _ := m.Group("http").Get("method"); method != "GET" {
+		t.Errorf("merged group method = %v, want GET", method)
+	}
+}
+
+func TestMetadataClone(t *testing.T) {
+	orig := NewMetadata()
+	orig.Add("a", 1)
+
+	c := orig.Clone()
+	c.Add("b", 2)
+	c.Add("a", 10)
+
+	if _, ok := orig.Get("b"); ok {
+		t.Error("expected clone additions not to affect original")
+	}
+	if a, _ := orig.Get("a"); a != 1 {
+		t.Errorf("expected original value unchanged, got %v", a)
+	}
+}
+
+func TestMetadataGroup(t *testing.T) {
+	meta := NewMetadata()
+	meta.Add("user", "alice")
+	http := meta.Group("http")
+	http.Add("method", "GET")
+	meta.Group("http").Add("status", 200)
+	meta.Group("http").Group("timing").Add("ms", 12)
+
+	var fields []string
+	meta.walk("", func(key string, value any) {
+		fields = append(fields, fmt.Sprintf("%s=%v", key, value))
+	})
+	want := []string{"user=alice", "http.method=GET", "http.status=200", "http.timing.ms=12"}
+	if !slices.Equal(fields, want) {
+		t.Errorf("got %q, want %q", fields, want)
+	}
+
+	// A field that isn't a group is replaced in place
+	meta.Add("db", "postgres")
+	meta.Group("db").Add("table", "users")
+	if keys := meta.keys(); !slices.Equal(keys, []string{"user", "http", "db"}) {
+		t.Errorf("keys = %q, want the group in the field's position", keys)
+	}
+}
+
+func TestMetadataCloneCopiesGroups(t *testing.T) {
+	orig := NewMetadata()
+	orig.Group("http").Add("method", "GET")
+
+	c := orig.Clone()
+	c.Group("http").Add("status", 500)
+
+	if _, ok := orig.Group("http").Get("status"); ok {
+		t.Error("expected changes to the clone's group not to affect the original")
+	}
+}
+
+func TestMetadataFromPairs(t *testing.T) {
+	meta := metadataFromPairs([]any{"userID", 456, 7, "seven", "dangling"})
+
+	if got := meta.keys(); !slices.Equal(got, []string{"userID", "7", "dangling"}) {
+		t.Errorf("keys() = %v", got)
+	}
+	if v, _ := meta.Get("userID"); v != 456 {
+		t.Errorf("userID = %v, want 456", v)
+	}
+	if v, _ := meta.Get("7"); v != "seven" {
+		t.Errorf("7 = %v, want seven", v)
+	}
+	if v, _ := meta.Get("dangling"); v != MissingValue {
+		t.Errorf("dangling = %v, want %q", v, MissingValue)
+	}
+
+	if metadataFromPairs(nil) != nil {
+		t.Error("expected nil metadata for no pairs")
+	}
+}
+
+func TestLazyFieldEvaluatedOnlyWhenWritten(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(&PlainTextFormatter{}, LevelInfo, DefaultConfig())
+	logger.SetOutput(&buf)
+
+	calls := 0
+	dump := func() any {
+		calls++
+		return "expensive"
+	}
+
+	logger.WithField("dump", dump).Debug("filtered out")
+	if calls != 0 {
+		t.Fatalf("lazy field evaluated %d times for a filtered level, want 0", calls)
+	}
+
+	entry := logger.WithField("dump", dump)
+	entry.Info("written")
+	if calls != 1 {
+		t.Errorf("lazy field evaluated %d times, want 1", calls)
+	}
+	if !strings.Contains(buf.String(), "dump=expensive") {
+		t.Errorf("expected the evaluated value in the output, got %q", buf.String())
+	}
+
+	// The Entry keeps the function, so the next line evaluates it again
+	entry.Info("again")
+	if calls != 2 {
+		t.Errorf("lazy field evaluated %d times after two lines, want 2", calls)
+	}
+}
+
+func TestLazyFieldSeenByHooks(t *testing.T) {
+	logger := New(&PlainTextFormatter{}, LevelInfo, DefaultConfig())
+	logger.SetOutput(&bytes.Buffer{})
+
+	var got any
+	logger.AddHook(func(e LogEntry) { got, _ = e.Metadata.Get("n") })
+	logger.Infow("lazy", "n", func() any { return 42 })
+
+	if got != 42 {
+		t.Errorf("hook saw %v, want the evaluated value 42", got)
+	}
+}
+
+func TestLazyFieldPanicIsReported(t *testing.T) {
+	var buf bytes.Buffer
+	var errs []error
+	logger := New(&PlainTextFormatter{}, LevelInfo, DefaultConfig())
+	logger.SetOutput(&buf)
+	logger.SetErrorHandler(func(err error) { errs = append(errs, err) })
+
+	logger.Infow("lazy", "bad", func() any { panic("boom") })
+
+	if !strings.Contains(buf.String(), "bad=<panic: boom>") {
+		t.Errorf("expected the panic in place of the value, got %q", buf.String())
+	}
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), `lazy field "bad" panicked`) {
+		t.Errorf("expected the panic to be reported, got %v", errs)
+	}
+}
+
+func TestResolveLazyLeavesOriginal(t *testing.T) {
+	meta := NewMetadata()
+	meta.Add("plain", 1)
+	meta.Add("lazy", func() any { return 2 })
+
+	resolved := resolveLazy(meta, func(error) {})
+	if resolved.Data["lazy"] != 2 || resolved.Data["plain"] != 1 {
+		t.Errorf("resolved = %v, want plain=1 lazy=2", resolved.Data)
+	}
+	if _, ok := meta.Data["lazy"].(func() any); !ok {
+		t.Error("expected the original metadata to keep the function")
+	}
+
+	plain := NewMetadata()
+	plain.Add("k", "v")
+	if resolveLazy(plain, func(error) {}) != plain {
+		t.Error("expected metadata without lazy values to be returned as is")
+	}
+}
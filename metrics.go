@@ -0,0 +1,76 @@
+package logr
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// Metrics is a snapshot of how many entries a Logger has written since it
+// was created or since the last ResetMetrics. Only entries that made it
+// past level filtering, sampling, rate limits and processors are counted.
+type Metrics struct {
+	Total  uint64
+	Levels map[Level]uint64 // Levels with no entries are left out
+	Layers map[Layer]uint64 // Layers with no entries are left out
+}
+
+// metrics holds the live counters behind Logger.Metrics. The zero value is
+// ready to use.
+type metrics struct {
+	levels [LevelTest - LevelTrace + 1]atomic.Uint64 // Indexed by level - LevelTrace
+	layers sync.Map                                  // Layer -> *atomic.Uint64
+}
+
+// count records one written entry. It only touches atomics, plus a map
+// insert the first time a layer is seen.
+func (m *metrics) count(level Level, layer Layer) {
+	if level >= LevelTrace && level <= LevelTest {
+		m.levels[level-LevelTrace].Add(1)
+	}
+
+	counter, ok := m.layers.Load(layer)
+	if !ok {
+		counter, _ = m.layers.LoadOrStore(layer, new(atomic.Uint64))
+	}
+	counter.(*atomic.Uint64).Add(1)
+}
+
+// Metrics returns the number of entries written so far, per level and per
+// layer, e.g. to serve from an admin endpoint and spot a spike in errors:
+//
+//	m := logr.Get().Metrics()
+//	fmt.Fprintf(w, "errors %d\n", m.Levels[logr.LevelError])
+//
+// Counting is lock-free, so concurrent logging may land between reading
+// one counter and the next.
+func (l *Logger) Metrics() Metrics {
+	m := Metrics{
+		Levels: make(map[Level]uint64),
+		Layers: make(map[Layer]uint64),
+	}
+	for i := range l.metrics.levels {
+		if n := l.metrics.levels[i].Load(); n > 0 {
+			m.Levels[LevelTrace+Level(i)] = n
+			m.Total += n
+		}
+	}
+	l.metrics.layers.Range(func(key, value any) bool {
+		if n := value.(*atomic.Uint64).Load(); n > 0 {
+			m.Layers[key.(Layer)] = n
+		}
+		return true
+	})
+	return m
+}
+
+// ResetMetrics sets every counter reported by Metrics back to zero, e.g.
+// at the start of each sampling window.
+func (l *Logger) ResetMetrics() {
+	for i := range l.metrics.levels {
+		l.metrics.levels[i].Store(0)
+	}
+	l.metrics.layers.Range(func(_, value any) bool {
+		value.(*atomic.Uint64).Store(0)
+		return true
+	})
+}
@@ -0,0 +1,91 @@
+package logr
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+)
+
+func TestMetricsCountsPerLevelAndLayer(t *testing.T) {
+	logger := New(&PlainTextFormatter{}, LevelDebug, DefaultConfig())
+	logger.SetOutput(&bytes.Buffer{})
+
+	logger.Info("one")
+	logger.Info("two")
+	logger.Error("three")
+	logger.Trace("filtered out")
+	logger.WithLayer("AUDIT").Warn("four")
+
+	m := logger.Metrics()
+	if m.Total != 4 {
+		t.Errorf("Total = %d, want 4", m.Total)
+	}
+	if m.Levels[LevelInfo] != 2 || m.Levels[LevelError] != 1 || m.Levels[LevelWarn] != 1 {
+		t.Errorf("Levels = %v, want INFO:2 WARN:1 ERROR:1", m.Levels)
+	}
+	if _, ok := m.Levels[LevelTrace]; ok {
+		t.Errorf("Levels = %v, want no entry for a filtered level", m.Levels)
+	}
+	if m.Layers["AUDIT"] != 1 {
+		t.Errorf("Layers[AUDIT] = %d, want 1", m.Layers["AUDIT"])
+	}
+	var layered uint64
+	for _, n := range m.Layers {
+		layered += n
+	}
+	if layered != m.Total {
+		t.Errorf("layer counts add up to %d, want %d", layered, m.Total)
+	}
+}
+
+func TestMetricsSkipDroppedEntries(t *testing.T) {
+	logger := New(&PlainTextFormatter{}, LevelInfo, DefaultConfig())
+	logger.SetOutput(&bytes.Buffer{})
+	logger.AddProcessor(func(e *LogEntry) bool { return e.Message != "drop" })
+
+	logger.Info("keep")
+	logger.Info("drop")
+
+	if m := logger.Metrics(); m.Total != 1 {
+		t.Errorf("Total = %d, want 1", m.Total)
+	}
+}
+
+func TestResetMetrics(t *testing.T) {
+	logger := New(&PlainTextFormatter{}, LevelInfo, DefaultConfig())
+	logger.SetOutput(&bytes.Buffer{})
+
+	logger.Error("before")
+	logger.ResetMetrics()
+
+	m := logger.Metrics()
+	if m.Total != 0 || len(m.Levels) != 0 || len(m.Layers) != 0 {
+		t.Errorf("Metrics() after reset = %+v, want empty", m)
+	}
+
+	logger.Error("after")
+	if m := logger.Metrics(); m.Levels[LevelError] != 1 {
+		t.Errorf("Levels[ERROR] = %d, want 1", m.Levels[LevelError])
+	}
+}
+
+func TestMetricsConcurrent(t *testing.T) {
+	logger := New(&PlainTextFormatter{}, LevelInfo, DefaultConfig())
+	logger.SetOutput(&bytes.Buffer{})
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				logger.Info("tick")
+			}
+		}()
+	}
+	wg.Wait()
+
+	if m := logger.Metrics(); m.Levels[LevelInfo] != 800 {
+		t.Errorf("Levels[INFO] = %d, want 800", m.Levels[LevelInfo])
+	}
+}
@@ -0,0 +1,231 @@
+package logr
+
+import (
+	"fmt"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	defaultMaxBuffered   = 1000
+	defaultDialTimeout   = 5 * time.Second
+	defaultRetryInterval = time.Second
+	defaultWriteTimeout  = time.Second
+)
+
+// NetworkSink is an io.Writer that ships each line to a log collector over
+// TCP (newline-delimited) or UDP (one datagram per line):
+//
+//	sink := &logr.NetworkSink{Network: "tcp", Address: "collector:5170"}
+//	defer sink.Close()
+//	logger.AddSink(sink)
+//
+// Connecting happens in the background, so Write never waits on a dial.
+// A write that takes longer than WriteTimeout, e.g. to a collector that
+// stalls without closing the connection, counts as a lost connection.
+// While there is no connection, lines are held in a buffer of up to
+// MaxBuffered lines and sent once the connection is (re)established;
+// lines beyond that are dropped and counted by Dropped. Lines already
+// handed to the operating system when a TCP connection breaks may be
+// lost. NetworkSink is safe for concurrent use.
+type NetworkSink struct {
+	Network       string        // "tcp" or "udp"
+	Address       string        // host:port
+	MaxBuffered   int           // Zero means 1000
+	DialTimeout   time.Duration // Zero means 5s
+	RetryInterval time.Duration // Wait between failed dials; zero means 1s
+	WriteTimeout  time.Duration // Longest wait for one line to be sent; zero means 1s
+
+	mu      sync.Mutex
+	conn    net.Conn
+	pending [][]byte // Lines waiting for a connection
+	dialing bool
+	closed  bool
+	done    chan struct{} // Closed by Close to stop the dial loop
+	wg      sync.WaitGroup
+	dropped atomic.Uint64
+}
+
+func (s *NetworkSink) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return 0, fmt.Errorf("logr: write to network sink: %w", net.ErrClosed)
+	}
+
+	if s.conn != nil {
+		if err := s.send(s.conn, p); err == nil {
+			return len(p), nil
+		}
+		// Connection lost or stalled; keep the line and reconnect in the
+		// background
+		s.conn.Close()
+		s.conn = nil
+	}
+
+	s.buffer(p)
+	s.startDialing()
+	return len(p), nil
+}
+
+// Dropped returns how many lines were discarded because the buffer was
+// full while disconnected, or still buffered when the sink was closed.
+func (s *NetworkSink) Dropped() uint64 {
+	return s.dropped.Load()
+}
+
+// Close stops reconnecting and closes the connection. Lines still waiting
+// for a connection are discarded and counted as dropped.
+func (s *NetworkSink) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	if s.done != nil {
+		close(s.done)
+	}
+	s.dropped.Add(uint64(len(s.pending)))
+	s.pending = nil
+
+	var err error
+	if s.conn != nil {
+		err = s.conn.Close()
+		s.conn = nil
+	}
+	s.mu.Unlock()
+
+	s.wg.Wait()
+	return err
+}
+
+// buffer queues a copy of p, dropping it if the buffer is full. Callers
+// must hold s.mu.
+func (s *NetworkSink) buffer(p []byte) {
+	if len(s.pending) >= s.maxBuffered() {
+		s.dropped.Add(1)
+		return
+	}
+	s.pending = append(s.pending, append([]byte(nil), p...))
+}
+
+// requeue puts lines that could not be sent back in front of the buffer,
+// dropping the newest lines beyond MaxBuffered. Callers must hold s.mu.
+func (s *NetworkSink) requeue(lines [][]byte) {
+	if s.closed {
+		s.dropped.Add(uint64(len(lines)))
+		return
+	}
+	pending := append(lines, s.pending...)
+	if limit := s.maxBuffered(); len(pending) > limit {
+		s.dropped.Add(uint64(len(pending) - limit))
+		pending = pending[:limit]
+	}
+	s.pending = pending
+}
+
+func (s *NetworkSink) maxBuffered() int {
+	if s.MaxBuffered <= 0 {
+		return defaultMaxBuffered
+	}
+	return s.MaxBuffered
+}
+
+// send writes one line to conn within WriteTimeout.
+func (s *NetworkSink) send(conn net.Conn, p []byte) error {
+	timeout := s.WriteTimeout
+	if timeout <= 0 {
+		timeout = defaultWriteTimeout
+	}
+	if err := conn.SetWriteDeadline(time.Now().Add(timeout)); err != nil {
+		return err
+	}
+	_, err := conn.Write(p)
+	return err
+}
+
+// startDialing launches the dial loop unless it is already running.
+// Callers must hold s.mu.
+func (s *NetworkSink) startDialing() {
+	if s.dialing {
+		return
+	}
+	if s.done == nil {
+		s.done = make(chan struct{})
+	}
+	s.dialing = true
+	s.wg.Add(1)
+	go s.dialLoop(s.done)
+}
+
+// dialLoop dials until it connects and sends the buffered lines, or until
+// the sink is closed.
+func (s *NetworkSink) dialLoop(done chan struct{}) {
+	defer s.wg.Done()
+
+	dialTimeout := s.DialTimeout
+	if dialTimeout <= 0 {
+		dialTimeout = defaultDialTimeout
+	}
+	retry := s.RetryInterval
+	if retry <= 0 {
+		retry = defaultRetryInterval
+	}
+
+	for {
+		conn, err := net.DialTimeout(s.Network, s.Address, dialTimeout)
+		if err == nil {
+			if s.flushPending(conn) {
+				return
+			}
+			conn.Close()
+		}
+
+		select {
+		case <-done:
+			s.mu.Lock()
+			s.dialing = false
+			s.mu.Unlock()
+			return
+		case <-time.After(retry):
+		}
+	}
+}
+
+// flushPending sends the buffered lines over conn in order, then makes it
+// the sink's connection and reports true. The lines are sent without
+// holding s.mu, so Write keeps buffering meanwhile instead of waiting on a
+// slow collector. It reports false if a line could not be sent, keeping
+// the unsent ones, or if the sink was closed.
+func (s *NetworkSink) flushPending(conn net.Conn) bool {
+	for {
+		s.mu.Lock()
+		if s.closed {
+			s.dialing = false
+			s.mu.Unlock()
+			return false
+		}
+		if len(s.pending) == 0 {
+			s.conn = conn
+			s.dialing = false
+			s.mu.Unlock()
+			return true
+		}
+		lines := s.pending
+		s.pending = nil
+		s.mu.Unlock()
+
+		for i, line := range lines {
+			if err := s.send(conn, line); err != nil {
+				s.mu.Lock()
+				s.requeue(lines[i:])
+				s.mu.Unlock()
+				return false
+			}
+		}
+	}
+}
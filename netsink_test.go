@@ -0,0 +1,205 @@
+package logr
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"net"
+	"sync"
+	"testing"
+	"time"
+)
+
+// readLines accepts one connection on ln and returns the first n lines.
+func readLines(t *testing.T, ln net.Listener, n int) []string {
+	t.Helper()
+	ln.(*net.TCPListener).SetDeadline(time.Now().Add(5 * time.Second))
+	conn, err := ln.Accept()
+	if err != nil {
+		t.Fatalf("Accept() error = %v", err)
+	}
+	defer conn.Close()
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	var lines []string
+	scanner := bufio.NewScanner(conn)
+	for len(lines) < n && scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	if len(lines) < n {
+		t.Fatalf("got %d lines %q, want %d (err %v)", len(lines), lines, n, scanner.Err())
+	}
+	return lines
+}
+
+func TestNetworkSinkTCP(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("cannot listen on TCP: %v", err)
+	}
+	defer ln.Close()
+
+	sink := &NetworkSink{Network: "tcp", Address: ln.Addr().String()}
+	defer sink.Close()
+
+	logger := New(&MockFormatter{}, LevelInfo, DefaultConfig())
+	logger.SetOutput(io.Discard)
+	logger.AddSink(sink)
+
+	logger.Info("first")
+	logger.Info("second")
+
+	lines := readLines(t, ln, 2)
+	if lines[0] != "first" || lines[1] != "second" {
+		t.Errorf("got lines %q", lines)
+	}
+}
+
+func TestNetworkSinkBuffersUntilConnected(t *testing.T) {
+	// Reserve a port, then free it so the first dials fail
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("cannot listen on TCP: %v", err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+
+	sink := &NetworkSink{Network: "tcp", Address: addr, RetryInterval: 10 * time.Millisecond}
+	defer sink.Close()
+
+	sink.Write([]byte("queued 1\n"))
+	sink.Write([]byte("queued 2\n"))
+
+	ln, err = net.Listen("tcp", addr)
+	if err != nil {
+		t.Skipf("cannot reuse port: %v", err)
+	}
+	defer ln.Close()
+
+	lines := readLines(t, ln, 2)
+	if lines[0] != "queued 1" || lines[1] != "queued 2" {
+		t.Errorf("got lines %q", lines)
+	}
+}
+
+func TestNetworkSinkDropsBeyondBuffer(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("cannot listen on TCP: %v", err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+
+	sink := &NetworkSink{Network: "tcp", Address: addr, MaxBuffered: 2, RetryInterval: time.Hour}
+
+	for i := 0; i < 5; i++ {
+		if _, err := sink.Write([]byte("line\n")); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+	if got := sink.Dropped(); got != 3 {
+		t.Errorf("Dropped() = %d, want 3", got)
+	}
+
+	// Close discards what is still buffered
+	sink.Close()
+	if got := sink.Dropped(); got != 5 {
+		t.Errorf("Dropped() after Close = %d, want 5", got)
+	}
+	if _, err := sink.Write([]byte("late\n")); err == nil {
+		t.Error("expected Write after Close to fail")
+	}
+}
+
+func TestNetworkSinkStalledCollector(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("cannot listen on TCP: %v", err)
+	}
+	defer ln.Close()
+
+	// Accept connections but never read from them
+	var mu sync.Mutex
+	var conns []net.Conn
+	defer func() {
+		mu.Lock()
+		defer mu.Unlock()
+		for _, c := range conns {
+			c.Close()
+		}
+	}()
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			mu.Lock()
+			conns = append(conns, conn)
+			mu.Unlock()
+		}
+	}()
+
+	sink := &NetworkSink{
+		Network:       "tcp",
+		Address:       ln.Addr().String(),
+		MaxBuffered:   4,
+		RetryInterval: 10 * time.Millisecond,
+		WriteTimeout:  50 * time.Millisecond,
+	}
+	defer sink.Close()
+
+	sink.Write([]byte("hello\n"))
+	for deadline := time.Now().Add(5 * time.Second); ; time.Sleep(time.Millisecond) {
+		sink.mu.Lock()
+		connected := sink.conn != nil
+		sink.mu.Unlock()
+		if connected {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("sink never connected")
+		}
+	}
+
+	// Enough to fill the socket buffers many times over
+	line := append(bytes.Repeat([]byte("x"), 1<<20), '\n')
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for range 200 {
+			sink.Write(line)
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("Write blocked on a stalled collector")
+	}
+	if sink.Dropped() == 0 {
+		t.Error("expected lines to be dropped once the collector stalled")
+	}
+}
+
+func TestNetworkSinkUDP(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("cannot listen on UDP: %v", err)
+	}
+	defer conn.Close()
+
+	sink := &NetworkSink{Network: "udp", Address: conn.LocalAddr().String()}
+	defer sink.Close()
+
+	sink.Write([]byte("datagram\n"))
+
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	buf := make([]byte, 1024)
+	n, _, err := conn.ReadFrom(buf)
+	if err != nil {
+		t.Fatalf("ReadFrom() error = %v", err)
+	}
+	if string(buf[:n]) != "datagram\n" {
+		t.Errorf("got %q", buf[:n])
+	}
+}
@@ -0,0 +1,55 @@
+package logr
+
+import "sync"
+
+// maxPooledFields caps the size of metadata returned to the pool, so one
+// unusually large entry doesn't pin a big map for the life of the process.
+const maxPooledFields = 64
+
+var entryPool = sync.Pool{
+	New: func() any { return new(LogEntry) },
+}
+
+var metadataPool = sync.Pool{
+	New: func() any { return &Metadata{Data: make(map[string]any)} },
+}
+
+// acquireEntry returns a pooled *LogEntry holding a copy of entry, for the
+// places that need a pointer without sending entry to the heap.
+func acquireEntry(entry LogEntry) *LogEntry {
+	e := entryPool.Get().(*LogEntry)
+	*e = entry
+	return e
+}
+
+func releaseEntry(e *LogEntry) {
+	*e = LogEntry{}
+	entryPool.Put(e)
+}
+
+// acquireMetadata returns empty metadata from the pool. Only metadata the
+// logger builds for itself comes from here; anything a caller hands in is
+// never recycled.
+func acquireMetadata() *Metadata {
+	return metadataPool.Get().(*Metadata)
+}
+
+func releaseMetadata(m *Metadata) {
+	if m == nil || len(m.Data) > maxPooledFields {
+		return
+	}
+	clear(m.Data)
+	clear(m.order)
+	m.order = m.order[:0]
+	metadataPool.Put(m)
+}
+
+// releaseOwned recycles metadata the logger built for a single log call
+// once that call has returned, unless something may still hold on to it:
+// the async writer, a hook, a processor or an entry sink.
+func (l *Logger) releaseOwned(meta *Metadata) {
+	if l.async != nil || l.retains.Load() {
+		return
+	}
+	releaseMetadata(meta)
+}
@@ -0,0 +1,104 @@
+package logr
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestReleasedMetadataIsReset(t *testing.T) {
+	meta := acquireMetadata()
+	meta.Add("requestID", "abc123")
+	releaseMetadata(meta)
+
+	for i := 0; i < 10; i++ {
+		m := acquireMetadata()
+		if len(m.Data) != 0 || len(m.order) != 0 {
+			t.Fatalf("acquireMetadata() returned %d fields, want none", len(m.Data))
+		}
+		defer releaseMetadata(m)
+	}
+}
+
+func TestHookKeepsMetadataAfterLogging(t *testing.T) {
+	logger := New(&PlainTextFormatter{}, LevelInfo, DefaultConfig())
+	logger.SetOutput(&bytes.Buffer{})
+
+	var captured []LogEntry
+	logger.AddHook(func(entry LogEntry) {
+		captured = append(captured, entry)
+	})
+
+	logger.Infow("first", "requestID", "abc123")
+	logger.WithField("userID", 42).Info("second")
+
+	if len(captured) != 2 {
+		t.Fatalf("captured %d entries, want 2", len(captured))
+	}
+	if got, _ := captured[0].Metadata.Get("requestID"); got != "abc123" {
+		t.Errorf("first entry requestID = %v, want abc123", got)
+	}
+	if got, _ := captured[1].Metadata.Get("userID"); got != 42 {
+		t.Errorf("second entry userID = %v, want 42", got)
+	}
+}
+
+func TestEntrySinkKeepsMetadataAfterLogging(t *testing.T) {
+	logger := New(&PlainTextFormatter{}, LevelInfo, DefaultConfig())
+	logger.SetOutput(&bytes.Buffer{})
+
+	var captured []LogEntry
+	logger.AddEntrySink(SinkFunc(func(entry LogEntry, _ string) error {
+		captured = append(captured, entry)
+		return nil
+	}))
+
+	logger.Infow("first", "requestID", "abc123")
+	logger.Infow("second", "requestID", "def456")
+
+	if len(captured) != 2 {
+		t.Fatalf("captured %d entries, want 2", len(captured))
+	}
+	if got, _ := captured[0].Metadata.Get("requestID"); got != "abc123" {
+		t.Errorf("first entry requestID = %v, want abc123", got)
+	}
+}
+
+func TestPooledLoggingConcurrent(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(&PlainTextFormatter{}, LevelInfo, DefaultConfig())
+	logger.SetOutput(&buf)
+	entry := logger.WithField("service", "api")
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				logger.Infow("pair", "id", fmt.Sprintf("%d-%d", g, i))
+				entry.Info("bound")
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 800 {
+		t.Fatalf("got %d lines, want 800", len(lines))
+	}
+	for _, line := range lines {
+		switch {
+		case strings.Contains(line, "pair"):
+			if !strings.Contains(line, "id=") || strings.Contains(line, "service=") {
+				t.Errorf("pair line has wrong fields: %q", line)
+			}
+		case strings.Contains(line, "bound"):
+			if !strings.Contains(line, "service=api") || strings.Contains(line, "id=") {
+				t.Errorf("bound line has wrong fields: %q", line)
+			}
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package logr
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// tokenBucket allows up to rate lines per second, with bursts of up to
+// rate lines.
+type tokenBucket struct {
+	rate   float64
+	tokens float64
+	last   time.Time
+}
+
+func (b *tokenBucket) allow(now time.Time) bool {
+	elapsed := now.Sub(b.last).Seconds()
+	if elapsed > 0 {
+		b.tokens = min(b.rate, b.tokens+elapsed*b.rate)
+		b.last = now
+	}
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// rateLimiter caps the lines per second of individual layers.
+type rateLimiter struct {
+	mu      sync.Mutex
+	buckets map[Layer]*tokenBucket
+	dropped map[Layer]*atomic.Uint64
+}
+
+func newRateLimiter() *rateLimiter {
+	return &rateLimiter{
+		buckets: make(map[Layer]*tokenBucket),
+		dropped: make(map[Layer]*atomic.Uint64),
+	}
+}
+
+// allow reports whether a line from layer fits in its budget, counting it
+// as dropped otherwise. Layers without a limit are always allowed.
+func (r *rateLimiter) allow(layer Layer) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	bucket, ok := r.buckets[layer]
+	if !ok || bucket.allow(nowFunc()) {
+		return true
+	}
+	r.dropped[layer].Add(1)
+	return false
+}
+
+// SetRateLimit caps layer at perSecond lines per second using a token
+// bucket; lines over the limit are dropped and counted by DroppedCount.
+// perSecond <= 0 removes the limit. Warn and above are limited too unless
+// Config.RateLimitBypassWarn is set.
+func (l *Logger) SetRateLimit(layer Layer, perSecond int) {
+	r := l.limiter
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if perSecond <= 0 {
+		delete(r.buckets, layer)
+		return
+	}
+	r.buckets[layer] = &tokenBucket{
+		rate:   float64(perSecond),
+		tokens: float64(perSecond),
+		last:   nowFunc(),
+	}
+	if r.dropped[layer] == nil {
+		r.dropped[layer] = new(atomic.Uint64)
+	}
+}
+
+// DroppedCount returns how many lines from layer were dropped by its rate
+// limit.
+func (l *Logger) DroppedCount(layer Layer) uint64 {
+	r := l.limiter
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if counter := r.dropped[layer]; counter != nil {
+		return counter.Load()
+	}
+	return 0
+}
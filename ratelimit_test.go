@@ -0,0 +1,91 @@
+package logr
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+// routeTestPackageTo makes logs from this test package resolve to layer.
+func routeTestPackageTo(logger *Logger, layer Layer) {
+	logger.registry[getCurrentPackage(2)] = &packageConfig{explicitLayer: stringPtr(layer.String())}
+}
+
+func TestSetRateLimit(t *testing.T) {
+	now := time.Date(2025, 9, 29, 12, 0, 0, 0, time.UTC)
+	setNow(t, now)
+
+	var buf bytes.Buffer
+	logger := New(&MockFormatter{}, LevelInfo, DefaultConfig())
+	logger.SetOutput(&buf)
+	routeTestPackageTo(logger, LayerDB)
+	logger.SetRateLimit(LayerDB, 3)
+
+	for i := 0; i < 5; i++ {
+		logger.Info("burst")
+	}
+	if got := strings.Count(buf.String(), "\n"); got != 3 {
+		t.Errorf("expected 3 lines within the first second, got %d", got)
+	}
+	if got := logger.DroppedCount(LayerDB); got != 2 {
+		t.Errorf("expected 2 dropped lines, got %d", got)
+	}
+
+	// Half a second refills 1.5 tokens
+	setNow(t, now.Add(500*time.Millisecond))
+	buf.Reset()
+	for i := 0; i < 3; i++ {
+		logger.Info("refill")
+	}
+	if got := strings.Count(buf.String(), "\n"); got != 1 {
+		t.Errorf("expected 1 line after refill, got %d", got)
+	}
+
+	if got := logger.DroppedCount(LayerHTTP); got != 0 {
+		t.Errorf("expected no drops for an unlimited layer, got %d", got)
+	}
+}
+
+func TestSetRateLimitRemove(t *testing.T) {
+	setNow(t, time.Date(2025, 9, 29, 12, 0, 0, 0, time.UTC))
+
+	var buf bytes.Buffer
+	logger := New(&MockFormatter{}, LevelInfo, DefaultConfig())
+	logger.SetOutput(&buf)
+	routeTestPackageTo(logger, LayerDB)
+	logger.SetRateLimit(LayerDB, 1)
+	logger.SetRateLimit(LayerDB, 0)
+
+	for i := 0; i < 5; i++ {
+		logger.Info("unlimited")
+	}
+	if got := strings.Count(buf.String(), "\n"); got != 5 {
+		t.Errorf("expected limit removed, got %d lines", got)
+	}
+}
+
+func TestRateLimitBypassWarn(t *testing.T) {
+	setNow(t, time.Date(2025, 9, 29, 12, 0, 0, 0, time.UTC))
+
+	config := DefaultConfig()
+	config.RateLimitBypassWarn = true
+
+	var buf bytes.Buffer
+	logger := New(&MockFormatter{}, LevelInfo, config)
+	logger.SetOutput(&buf)
+	routeTestPackageTo(logger, LayerDB)
+	logger.SetRateLimit(LayerDB, 1)
+
+	for i := 0; i < 3; i++ {
+		logger.Info("info")
+		logger.Error("error")
+	}
+
+	if got := strings.Count(buf.String(), "error\n"); got != 3 {
+		t.Errorf("expected every error to bypass the limit, got %d", got)
+	}
+	if got := strings.Count(buf.String(), "info\n"); got != 1 {
+		t.Errorf("expected info to stay limited, got %d", got)
+	}
+}
@@ -0,0 +1,97 @@
+package logr
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// PanicMessage is the message of the entry logged for a recovered panic.
+const PanicMessage = "panic recovered"
+
+// RecoverAndLog recovers a panic in the calling goroutine and logs it at
+// LevelError, then lets the function return normally. It must be deferred
+// directly, since recover only works from the deferred call itself:
+//
+//	func (w *Worker) run(job Job) {
+//		defer logger.RecoverAndLog()
+//		job.Do()
+//	}
+//
+// The entry's Err holds the panic value, wrapped when it is an error, and
+// its Stack the trace from where the panic happened, whether or not
+// CaptureStackOnError is set. Its layer, file and line are those of the
+// panicking function. Nothing is logged when there is no panic.
+func (l *Logger) RecoverAndLog() {
+	if r := recover(); r != nil {
+		l.logPanic(r)
+	}
+}
+
+// RecoverAndRepanic is RecoverAndLog for panics that must still crash the
+// program, or reach a recover further up: after logging, and flushing an
+// async logger so the entry isn't lost, it panics again with the same
+// value.
+//
+//	func main() {
+//		defer logger.RecoverAndRepanic()
+//		run()
+//	}
+func (l *Logger) RecoverAndRepanic() {
+	if r := recover(); r != nil {
+		l.logPanic(r)
+		l.Flush()
+		panic(r)
+	}
+}
+
+// logPanic writes the entry for the recovered value r. It is called from
+// the deferred function, so the panicking frames are still on the stack.
+func (l *Logger) logPanic(r any) {
+	if l.closed.Load() || !l.mayLog(LevelError) {
+		return
+	}
+
+	packagePath, file, line := panicSite()
+	entry := LogEntry{
+		Level:   LevelError,
+		Layer:   Layer(resolveLayer(l, packagePath)),
+		Message: PanicMessage,
+		Err:     panicError(r),
+		// Skip runtime.Callers, stackFrom, logPanic and the Recover method
+		Stack: stackFrom(4, isPanicFrame),
+	}
+	l.emit(entry, file, line)
+}
+
+// panicError turns a recovered value into the entry's error, keeping an
+// error value in the chain for errors.Is and errors.As.
+func panicError(r any) error {
+	if err, ok := r.(error); ok {
+		return fmt.Errorf("panic: %w", err)
+	}
+	return fmt.Errorf("panic: %v", r)
+}
+
+// isPanicFrame reports whether frame sits between the deferred Recover
+// call and the function that panicked: logr's own frames and the runtime's
+// panic machinery, such as runtime.gopanic and runtime.sigpanic.
+func isPanicFrame(frame runtime.Frame) bool {
+	return isInternalFrame(frame) || packageFromFunc(frame.Function) == "runtime"
+}
+
+// panicSite is getCaller for a panic: the package, file and line of the
+// function that panicked.
+func panicSite() (packagePath string, file string, line int) {
+	var pcs [32]uintptr
+	n := runtime.Callers(2, pcs[:])
+
+	packagePath = unknownPackage
+	for _, pc := range pcs[:n] {
+		frame := lookupFrame(pc)
+		packagePath, file, line = frame.pkg, frame.file, frame.line
+		if !isInternal(frame.pkg, frame.file) && frame.pkg != "runtime" {
+			break
+		}
+	}
+	return packagePath, file, line
+}
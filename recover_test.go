@@ -0,0 +1,131 @@
+package logr
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func newRecoverLogger() *Logger {
+	logger := New(JSONFormatter{}, LevelInfo, DefaultConfig())
+	logger.SetOutput(io.Discard)
+	return logger
+}
+
+func panickingWork(v any) {
+	panic(v)
+}
+
+func recoverInto(logger *Logger, v any) {
+	defer logger.RecoverAndLog()
+	panickingWork(v)
+}
+
+func TestRecoverAndLog(t *testing.T) {
+	logger := newRecoverLogger()
+	var got []LogEntry
+	logger.AddHook(func(e LogEntry) { got = append(got, e) })
+
+	recoverInto(logger, "boom")
+
+	if len(got) != 1 {
+		t.Fatalf("got %d entries, want 1", len(got))
+	}
+	e := got[0]
+	if e.Level != LevelError || e.Message != PanicMessage {
+		t.Errorf("got %s %q, want ERROR %q", e.Level, e.Message, PanicMessage)
+	}
+	if e.Err == nil || e.Err.Error() != "panic: boom" {
+		t.Errorf("Err = %v, want panic: boom", e.Err)
+	}
+	first, _, _ := strings.Cut(e.Stack, "\n")
+	if want := "github.com/cheezecakee/logr.panickingWork()"; first != want {
+		t.Errorf("stack starts with %q, want %q\n%s", first, want, e.Stack)
+	}
+	if e.Layer != Layer(resolveLayer(logger, getCurrentPackage(1))) {
+		t.Errorf("Layer = %s, want the panicking package's layer", e.Layer)
+	}
+}
+
+func TestRecoverAndLogKeepsErrorChain(t *testing.T) {
+	logger := newRecoverLogger()
+	var got error
+	logger.AddHook(func(e LogEntry) { got = e.Err })
+
+	cause := errors.New("disk full")
+	recoverInto(logger, cause)
+
+	if !errors.Is(got, cause) {
+		t.Errorf("Err = %v, want it to wrap the panic value", got)
+	}
+}
+
+func TestRecoverAndLogRuntimeError(t *testing.T) {
+	logger := newRecoverLogger()
+	var got LogEntry
+	logger.AddHook(func(e LogEntry) { got = e })
+
+	func() {
+		defer logger.RecoverAndLog()
+		var m map[string]int
+		m["x"] = 1
+	}()
+
+	if got.Err == nil || !strings.Contains(got.Err.Error(), "nil map") {
+		t.Errorf("Err = %v, want the runtime error", got.Err)
+	}
+	if strings.HasPrefix(got.Stack, "runtime.") {
+		t.Errorf("stack starts in the runtime:\n%s", got.Stack)
+	}
+}
+
+func TestRecoverAndLogNoPanic(t *testing.T) {
+	logger := newRecoverLogger()
+	calls := 0
+	logger.AddHook(func(LogEntry) { calls++ })
+
+	func() {
+		defer logger.RecoverAndLog()
+	}()
+
+	if calls != 0 {
+		t.Errorf("got %d entries without a panic, want 0", calls)
+	}
+}
+
+func TestRecoverAndRepanic(t *testing.T) {
+	logger := newRecoverLogger()
+	calls := 0
+	logger.AddHook(func(LogEntry) { calls++ })
+
+	var repanicked any
+	func() {
+		defer func() { repanicked = recover() }()
+		defer logger.RecoverAndRepanic()
+		panickingWork("boom")
+	}()
+
+	if repanicked != "boom" {
+		t.Errorf("re-panicked with %v, want boom", repanicked)
+	}
+	if calls != 1 {
+		t.Errorf("got %d entries, want 1", calls)
+	}
+}
+
+func TestRecoverAndRepanicClosedLogger(t *testing.T) {
+	logger := newRecoverLogger()
+	logger.Close()
+
+	var repanicked any
+	func() {
+		defer func() { repanicked = recover() }()
+		defer logger.RecoverAndRepanic()
+		panickingWork("boom")
+	}()
+
+	if repanicked != "boom" {
+		t.Errorf("a closed logger must still re-panic, got %v", repanicked)
+	}
+}
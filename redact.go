@@ -0,0 +1,104 @@
+package logr
+
+import "strings"
+
+// RedactedValue replaces the value of metadata fields matched by
+// Config.RedactKeys or Config.RedactContaining.
+const RedactedValue = "[REDACTED]"
+
+// redactor replaces sensitive metadata values before formatting, so the
+// same fields are hidden whichever formatter is in use.
+type redactor struct {
+	keys       map[string]struct{} // Lowercased exact matches
+	substrings []string            // Lowercased partial matches
+}
+
+// newRedactor returns nil when config has nothing to redact.
+func newRedactor(config Config) *redactor {
+	if len(config.RedactKeys) == 0 && len(config.RedactContaining) == 0 {
+		return nil
+	}
+
+	r := &redactor{keys: make(map[string]struct{}, len(config.RedactKeys))}
+	for _, key := range config.RedactKeys {
+		r.keys[strings.ToLower(key)] = struct{}{}
+	}
+	for _, sub := range config.RedactContaining {
+		if sub != "" {
+			r.substrings = append(r.substrings, strings.ToLower(sub))
+		}
+	}
+	return r
+}
+
+func (r *redactor) matches(key string) bool {
+	lower := strings.ToLower(key)
+	if _, ok := r.keys[lower]; ok {
+		return true
+	}
+	for _, sub := range r.substrings {
+		if strings.Contains(lower, sub) {
+			return true
+		}
+	}
+	return false
+}
+
+// redact returns meta with sensitive values replaced, including inside
+// Metadata.Group sections and nested map[string]any groups. meta itself
+// is never modified; a copy is made only when something needs redacting.
+func (r *redactor) redact(meta *Metadata) *Metadata {
+	if r == nil || meta == nil {
+		return meta
+	}
+
+	out := meta
+	for _, key := range meta.keys() {
+		value, changed := r.redactValue(key, meta.Data[key])
+		if !changed {
+			continue
+		}
+		if out == meta {
+			out = meta.Clone()
+		}
+		out.Data[key] = value
+	}
+	return out
+}
+
+// redactValue returns the value to log for key and whether it differs
+// from value.
+func (r *redactor) redactValue(key string, value any) (any, bool) {
+	if r.matches(key) {
+		return RedactedValue, true
+	}
+
+	if section, ok := value.(*Metadata); ok {
+		redacted := r.redact(section)
+		return redacted, redacted != section
+	}
+
+	group, ok := value.(map[string]any)
+	if !ok {
+		return value, false
+	}
+
+	var out map[string]any
+	for k, v := range group {
+		redacted, changed := r.redactValue(k, v)
+		if !changed {
+			continue
+		}
+		if out == nil {
+			out = make(map[string]any, len(group))
+			for ck, cv := range group {
+				out[ck] = cv
+			}
+		}
+		out[k] = redacted
+	}
+	if out == nil {
+		return value, false
+	}
+	return out, true
+}
@@ -0,0 +1,97 @@
+package logr
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRedactKeys(t *testing.T) {
+	r := newRedactor(Config{
+		RedactKeys:       []string{"password", "Authorization"},
+		RedactContaining: []string{"secret"},
+	})
+
+	meta := NewMetadata()
+	meta.Add("user", "alice")
+	meta.Add("Password", "hunter2")
+	meta.Add("authorization", "Bearer xyz")
+	meta.Add("clientSecretKey", "s3cr3t")
+	meta.Add("request", map[string]any{"token_secret": "t", "path": "/login"})
+
+	got := r.redact(meta)
+
+	for key, want := range map[string]any{
+		"user":            "alice",
+		"Password":        RedactedValue,
+		"authorization":   RedactedValue,
+		"clientSecretKey": RedactedValue,
+	} {
+		if value, _ := got.Get(key); value != want {
+			t.Errorf("%s = %v, want %v", key, value, want)
+		}
+	}
+
+	request, _ := got.Get("request")
+	group := request.(map[string]any)
+	if group["token_secret"] != RedactedValue || group["path"] != "/login" {
+		t.Errorf("expected nested secret redacted, got %v", group)
+	}
+
+	if value, _ := meta.Get("Password"); value != "hunter2" {
+		t.Error("expected original metadata to be left untouched")
+	}
+	if original, _ := meta.Get("request"); original.(map[string]any)["token_secret"] != "t" {
+		t.Error("expected original nested group to be left untouched")
+	}
+}
+
+func TestRedactGroups(t *testing.T) {
+	r := newRedactor(Config{RedactKeys: []string{"password"}})
+
+	meta := NewMetadata()
+	meta.Group("login").Add("user", "alice")
+	meta.Group("login").Add("password", "hunter2")
+
+	got := r.redact(meta)
+
+	if value, _ := got.Group("login").Get("password"); value != RedactedValue {
+		t.Errorf("password = %v, want %v", value, RedactedValue)
+	}
+	if value, _ := got.Group("login").Get("user"); value != "alice" {
+		t.Errorf("user = %v, want alice", value)
+	}
+	if value, _ := meta.Group("login").Get("password"); value != "hunter2" {
+		t.Error("expected original group to be left untouched")
+	}
+}
+
+func TestRedactNoMatchReturnsSameMetadata(t *testing.T) {
+	r := newRedactor(Config{RedactKeys: []string{"password"}})
+
+	meta := NewMetadata()
+	meta.Add("user", "alice")
+
+	if r.redact(meta) != meta {
+		t.Error("expected metadata without sensitive keys to be returned as-is")
+	}
+}
+
+func TestRedactAppliesToEveryFormatter(t *testing.T) {
+	formatters := []Formatter{&PlainTextFormatter{}, JSONFormatter{}, LogfmtFormatter{}}
+
+	for _, formatter := range formatters {
+		config := DefaultConfig()
+		config.RedactKeys = []string{"token"}
+
+		var buf bytes.Buffer
+		logger := New(formatter, LevelInfo, config)
+		logger.SetOutput(&buf)
+
+		logger.WithField("token", "abc123").Info("login")
+
+		if strings.Contains(buf.String(), "abc123") || !strings.Contains(buf.String(), RedactedValue) {
+			t.Errorf("%T: expected token to be redacted, got %q", formatter, buf.String())
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package logr
+
+import (
+	"strconv"
+	"sync"
+	"sync/atomic"
+)
+
+// SuppressedKey is the metadata field added to a sampled line, holding how
+// many lines from the same call site were skipped since the last one
+// written.
+const SuppressedKey = "suppressed"
+
+// sampler keeps one of every n lines per call site.
+type sampler struct {
+	every int
+
+	mu    sync.Mutex
+	sites map[string]uint64 // Lines seen per "file:line"
+
+	suppressed atomic.Uint64 // Total skipped across all call sites
+}
+
+// sample reports whether the line from file:line should be written and,
+// if so, how many lines from that site were skipped since the last one.
+func (s *sampler) sample(file string, line int) (keep bool, skipped uint64) {
+	site := file + ":" + strconv.Itoa(line)
+
+	s.mu.Lock()
+	seen := s.sites[site]
+	s.sites[site] = seen + 1
+	s.mu.Unlock()
+
+	if seen%uint64(s.every) != 0 {
+		s.suppressed.Add(1)
+		return false, 0
+	}
+	if seen == 0 {
+		return true, 0
+	}
+	return true, uint64(s.every - 1)
+}
+
+// SampleEvery writes only one of every n Debug and Info lines from each
+// call site; Warn and above always pass. Each written line after the first
+// carries a SuppressedKey field with the number of lines skipped since the
+// previous one. n <= 1 turns sampling off.
+func (l *Logger) SampleEvery(n int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if n <= 1 {
+		l.sampler = nil
+		return
+	}
+	l.sampler = &sampler{every: n, sites: make(map[string]uint64)}
+}
+
+// SuppressedCount returns how many lines sampling has skipped since the
+// last call to SampleEvery.
+func (l *Logger) SuppressedCount() uint64 {
+	l.mu.Lock()
+	s := l.sampler
+	l.mu.Unlock()
+	if s == nil {
+		return 0
+	}
+	return s.suppressed.Load()
+}
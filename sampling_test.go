@@ -0,0 +1,74 @@
+package logr
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestSampleEveryPerCallSite(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(&PlainTextFormatter{}, LevelDebug, DefaultConfig())
+	logger.SetOutput(&buf)
+	logger.SampleEvery(3)
+
+	for i := 0; i < 10; i++ {
+		logger.Info("hot loop")
+		logger.Debug("other site")
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	var hot, other []string
+	for _, line := range lines {
+		if strings.Contains(line, "hot loop") {
+			hot = append(hot, line)
+		} else if strings.Contains(line, "other site") {
+			other = append(other, line)
+		}
+	}
+
+	if len(hot) != 4 || len(other) != 4 {
+		t.Fatalf("expected 4 lines per call site, got %d and %d:\n%s", len(hot), len(other), buf.String())
+	}
+	if strings.Contains(hot[0], SuppressedKey) {
+		t.Errorf("expected first line without suppressed count, got %q", hot[0])
+	}
+	if !strings.HasSuffix(hot[1], "hot loop suppressed=2") {
+		t.Errorf("expected suppressed count on later lines, got %q", hot[1])
+	}
+	if got := logger.SuppressedCount(); got != 12 {
+		t.Errorf("expected 12 suppressed lines, got %d", got)
+	}
+}
+
+func TestSampleEveryPassesWarnAndError(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(&MockFormatter{}, LevelDebug, DefaultConfig())
+	logger.SetOutput(&buf)
+	logger.SampleEvery(100)
+
+	for i := 0; i < 5; i++ {
+		logger.Warn("warn")
+		logger.Error("error")
+	}
+
+	if got := strings.Count(buf.String(), "\n"); got != 10 {
+		t.Errorf("expected all 10 warn/error lines, got %d", got)
+	}
+}
+
+func TestSampleEveryDisable(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(&MockFormatter{}, LevelDebug, DefaultConfig())
+	logger.SetOutput(&buf)
+	logger.SampleEvery(10)
+	logger.SampleEvery(1)
+
+	for i := 0; i < 5; i++ {
+		logger.Info("line")
+	}
+
+	if got := strings.Count(buf.String(), "\n"); got != 5 {
+		t.Errorf("expected sampling disabled, got %d lines", got)
+	}
+}
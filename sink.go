@@ -0,0 +1,49 @@
+package logr
+
+import "io"
+
+// Sink receives each entry along with the line the formatter rendered for
+// it, so it can route or filter by level, layer or metadata without
+// parsing the line. Register one with Logger.AddEntrySink. The entry is
+// formatted once no matter how many sinks there are.
+type Sink interface {
+	Write(entry LogEntry, formatted string) error
+}
+
+// SinkFunc adapts a function to the Sink interface.
+type SinkFunc func(entry LogEntry, formatted string) error
+
+func (f SinkFunc) Write(entry LogEntry, formatted string) error {
+	return f(entry, formatted)
+}
+
+// LevelWriter is implemented by writers that need each line's level, such
+// as SyslogSink. Outputs and sinks that implement it receive WriteLevel
+// calls instead of Write.
+type LevelWriter interface {
+	io.Writer
+	WriteLevel(level Level, p []byte) (int, error)
+}
+
+// writerSink adapts an io.Writer added with Logger.AddSink to Sink.
+type writerSink struct {
+	w io.Writer
+}
+
+func (s writerSink) Write(entry LogEntry, formatted string) error {
+	return writeLine(s.w, entry.Level, formatted)
+}
+
+// writeLine writes formatted plus a newline to w, passing the level along
+// if w is a LevelWriter.
+func writeLine(w io.Writer, level Level, formatted string) error {
+	line := []byte(formatted + "\n")
+
+	var err error
+	if lw, ok := w.(LevelWriter); ok {
+		_, err = lw.WriteLevel(level, line)
+	} else {
+		_, err = w.Write(line)
+	}
+	return err
+}
@@ -0,0 +1,101 @@
+package logr
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestEntrySinkReceivesEntryAndLine(t *testing.T) {
+	var got []LogEntry
+	var lines []string
+
+	logger := New(&PlainTextFormatter{}, LevelInfo, DefaultConfig())
+	logger.SetOutput(&bytes.Buffer{})
+	logger.AddEntrySink(SinkFunc(func(entry LogEntry, formatted string) error {
+		got = append(got, entry)
+		lines = append(lines, formatted)
+		return nil
+	}))
+
+	logger.WithField("userID", 42).Warn("slow request")
+
+	if len(got) != 1 {
+		t.Fatalf("expected 1 entry, got %d", len(got))
+	}
+	if got[0].Level != LevelWarn || got[0].Message != "slow request" {
+		t.Errorf("unexpected entry %+v", got[0])
+	}
+	if v, _ := got[0].Metadata.Get("userID"); v != 42 {
+		t.Errorf("expected userID metadata, got %v", v)
+	}
+	if !strings.HasPrefix(lines[0], "[WARN]") || strings.HasSuffix(lines[0], "\n") {
+		t.Errorf("expected the formatted line without newline, got %q", lines[0])
+	}
+}
+
+func TestEntrySinkRoutesByLevel(t *testing.T) {
+	var errorsOnly bytes.Buffer
+
+	logger := New(&MockFormatter{}, LevelInfo, DefaultConfig())
+	logger.SetOutput(&bytes.Buffer{})
+	logger.AddEntrySink(SinkFunc(func(entry LogEntry, formatted string) error {
+		if entry.Level < LevelError {
+			return nil
+		}
+		errorsOnly.WriteString(formatted + "\n")
+		return nil
+	}))
+
+	logger.Info("fine")
+	logger.Error("broken")
+
+	if errorsOnly.String() != "broken\n" {
+		t.Errorf("expected only the error line, got %q", errorsOnly.String())
+	}
+}
+
+func TestEntrySinkErrorIsReported(t *testing.T) {
+	var reported []error
+
+	logger := New(&MockFormatter{}, LevelInfo, DefaultConfig())
+	logger.SetOutput(&bytes.Buffer{})
+	logger.SetErrorHandler(func(err error) { reported = append(reported, err) })
+	logger.AddEntrySink(SinkFunc(func(LogEntry, string) error {
+		return errors.New("collector down")
+	}))
+
+	logger.Info("message")
+
+	if len(reported) != 1 || !strings.Contains(reported[0].Error(), "collector down") {
+		t.Errorf("expected the sink error to be reported, got %v", reported)
+	}
+}
+
+func TestEntrySinkAsync(t *testing.T) {
+	var mu sync.Mutex
+	var layers []Layer
+
+	config := DefaultConfig()
+	config.Async = true
+	logger := New(&PlainTextFormatter{}, LevelInfo, config)
+	defer logger.Close()
+	logger.SetOutput(&bytes.Buffer{})
+	logger.AddEntrySink(SinkFunc(func(entry LogEntry, _ string) error {
+		mu.Lock()
+		defer mu.Unlock()
+		layers = append(layers, entry.Layer)
+		return nil
+	}))
+
+	logger.emit(LogEntry{Level: LevelInfo, Layer: LayerDB, Message: "query"}, "", 0)
+	logger.Flush()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(layers) != 1 || layers[0] != LayerDB {
+		t.Errorf("expected one DB entry, got %v", layers)
+	}
+}
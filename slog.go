@@ -0,0 +1,133 @@
+package logr
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+)
+
+// slogHandler adapts a Logger to slog.Handler.
+type slogHandler struct {
+	logger *Logger
+	groups []string      // Current group path from WithGroup
+	preset []slogPresets // Attributes from WithAttrs
+}
+
+// slogPresets are attributes added with WithAttrs under the group path
+// that was current at the time.
+type slogPresets struct {
+	groups []string
+	attrs  []slog.Attr
+}
+
+// NewSlogHandler returns a slog.Handler that writes through l, so code
+// using log/slog shares logr's formatters, outputs and layer detection:
+//
+//	slog.SetDefault(slog.New(logr.NewSlogHandler(logr.Get())))
+//
+// slog levels map to the closest logr Level, attributes become Metadata
+// and groups become Metadata.Group sections.
+func NewSlogHandler(l *Logger) slog.Handler {
+	return &slogHandler{logger: l}
+}
+
+func (h *slogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return !h.logger.closed.Load() && h.logger.mayLog(levelFromSlog(level))
+}
+
+func (h *slogHandler) Handle(_ context.Context, record slog.Record) error {
+	level := levelFromSlog(record.Level)
+	if h.logger.closed.Load() || !h.logger.mayLog(level) {
+		return nil
+	}
+
+	var meta, owned *Metadata
+	if len(h.preset) > 0 || record.NumAttrs() > 0 {
+		owned = acquireMetadata()
+		for _, p := range h.preset {
+			for _, a := range p.attrs {
+				addSlogAttr(owned, p.groups, a)
+			}
+		}
+		record.Attrs(func(a slog.Attr) bool {
+			addSlogAttr(owned, h.groups, a)
+			return true
+		})
+		if len(owned.Data) > 0 {
+			meta = owned
+		}
+	}
+
+	packagePath, file, line := callerFromPC(record.PC)
+	layer := Layer(resolveLayer(h.logger, packagePath))
+
+	entry := LogEntry{Level: level, Layer: layer, Message: record.Message, Metadata: meta}
+	h.logger.emit(entry, file, line)
+	h.logger.releaseOwned(owned)
+	return nil
+}
+
+func (h *slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	return &slogHandler{
+		logger: h.logger,
+		groups: h.groups,
+		preset: append(slices.Clip(h.preset), slogPresets{groups: h.groups, attrs: attrs}),
+	}
+}
+
+func (h *slogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &slogHandler{
+		logger: h.logger,
+		groups: append(slices.Clip(h.groups), name),
+		preset: h.preset,
+	}
+}
+
+// levelFromSlog maps a slog level to the logr level at or below it.
+func levelFromSlog(level slog.Level) Level {
+	switch {
+	case level < slog.LevelInfo:
+		return LevelDebug
+	case level < slog.LevelWarn:
+		return LevelInfo
+	case level < slog.LevelError:
+		return LevelWarn
+	default:
+		return LevelError
+	}
+}
+
+// addSlogAttr stores a under the given group path in meta, following the
+// slog.Handler rules: empty attributes are ignored, groups with an empty
+// key are inlined and empty groups are dropped.
+func addSlogAttr(meta *Metadata, groups []string, a slog.Attr) {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return
+	}
+
+	if a.Value.Kind() == slog.KindGroup {
+		attrs := a.Value.Group()
+		if len(attrs) == 0 {
+			return
+		}
+		if a.Key != "" {
+			groups = append(slices.Clip(groups), a.Key)
+		}
+		for _, ga := range attrs {
+			addSlogAttr(meta, groups, ga)
+		}
+		return
+	}
+
+	for _, name := range groups {
+		meta = meta.Group(name)
+	}
+	meta.Add(a.Key, a.Value.Any())
+}
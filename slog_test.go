@@ -0,0 +1,140 @@
+package logr
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+// decodeJSONLine decodes a single JSONFormatter line.
+func decodeJSONLine(t *testing.T, buf *bytes.Buffer) map[string]any {
+	t.Helper()
+	var decoded map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("invalid JSON %q: %v", buf.String(), err)
+	}
+	return decoded
+}
+
+func newSlogTestLogger(buf *bytes.Buffer) *slog.Logger {
+	logger := New(JSONFormatter{}, LevelDebug, DefaultConfig())
+	logger.SetOutput(buf)
+	return slog.New(NewSlogHandler(logger))
+}
+
+func TestSlogHandlerBasic(t *testing.T) {
+	var buf bytes.Buffer
+	sl := newSlogTestLogger(&buf)
+
+	sl.Warn("disk almost full", "percent", 91, "mount", "/data")
+
+	got := decodeJSONLine(t, &buf)
+	if got["level"] != "WARN" || got["message"] != "disk almost full" {
+		t.Errorf("unexpected level/message: %v", got)
+	}
+	if got["layer"] != "CHEEZECAKEE/LOGR" {
+		t.Errorf("expected layer of the slog caller, got %v", got["layer"])
+	}
+
+	data := got["metadata"].(map[string]any)
+	if data["percent"] != float64(91) || data["mount"] != "/data" {
+		t.Errorf("expected attributes as metadata, got %v", data)
+	}
+}
+
+func TestSlogHandlerGroupsAndAttrs(t *testing.T) {
+	var buf bytes.Buffer
+	sl := newSlogTestLogger(&buf)
+
+	sl.With("service", "api").
+		WithGroup("request").
+		With("id", "abc").
+		Info("handled", "status", 200, slog.Group("timing", "ms", 12))
+
+	data := decodeJSONLine(t, &buf)["metadata"].(map[string]any)
+
+	if data["service"] != "api" {
+		t.Errorf("expected top-level service attribute, got %v", data)
+	}
+	request, ok := data["request"].(map[string]any)
+	if !ok {
+		t.Fatalf("expected request group, got %v", data)
+	}
+	if request["id"] != "abc" || request["status"] != float64(200) {
+		t.Errorf("expected id and status inside request group, got %v", request)
+	}
+	if timing, ok := request["timing"].(map[string]any); !ok || timing["ms"] != float64(12) {
+		t.Errorf("expected nested timing group, got %v", request["timing"])
+	}
+}
+
+func TestSlogHandlerEmptyGroupOmitted(t *testing.T) {
+	var buf bytes.Buffer
+	sl := newSlogTestLogger(&buf)
+
+	sl.WithGroup("empty").Info("no attrs")
+
+	if _, ok := decodeJSONLine(t, &buf)["metadata"]; ok {
+		t.Errorf("expected no metadata for an empty group, got %q", buf.String())
+	}
+}
+
+func TestSlogHandlerLevels(t *testing.T) {
+	tests := []struct {
+		slogLevel slog.Level
+		want      Level
+	}{
+		{slog.LevelDebug - 4, LevelDebug},
+		{slog.LevelDebug, LevelDebug},
+		{slog.LevelInfo, LevelInfo},
+		{slog.LevelInfo + 2, LevelInfo},
+		{slog.LevelWarn, LevelWarn},
+		{slog.LevelError, LevelError},
+		{slog.LevelError + 4, LevelError},
+	}
+
+	for _, tt := range tests {
+		if got := levelFromSlog(tt.slogLevel); got != tt.want {
+			t.Errorf("levelFromSlog(%s) = %s, want %s", tt.slogLevel, got, tt.want)
+		}
+	}
+}
+
+func TestSlogHandlerEnabled(t *testing.T) {
+	logger := New(JSONFormatter{}, LevelWarn, DefaultConfig())
+	sl := slog.New(NewSlogHandler(logger))
+
+	if sl.Enabled(context.Background(), slog.LevelInfo) {
+		t.Error("expected Info disabled at LevelWarn")
+	}
+	if !sl.Enabled(context.Background(), slog.LevelError) {
+		t.Error("expected Error enabled at LevelWarn")
+	}
+}
+
+func TestSlogHandlerStackStartsAtCaller(t *testing.T) {
+	config := DefaultConfig()
+	config.CaptureStackOnError = true
+
+	var buf bytes.Buffer
+	logger := New(JSONFormatter{}, LevelInfo, config)
+	logger.SetOutput(&buf)
+	sl := slog.New(NewSlogHandler(logger))
+
+	sl.Error("boom")
+	stackViaMethod := decodeJSONLine(t, &buf)["stack"].(string)
+
+	buf.Reset()
+	slog.New(NewSlogHandler(logger)).Log(context.Background(), slog.LevelError, "boom")
+	stackViaLog := decodeJSONLine(t, &buf)["stack"].(string)
+
+	want := "github.com/cheezecakee/logr.TestSlogHandlerStackStartsAtCaller()"
+	for _, stack := range []string{stackViaMethod, stackViaLog} {
+		if !strings.HasPrefix(stack, want) {
+			t.Errorf("expected stack to start at %q, got %q", want, stack)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package logr
+
+import (
+	"maps"
+	"sync/atomic"
+)
+
+// ConfigSnapshot is a copy of a logger's runtime settings, taken by
+// SnapshotConfig and put back by RestoreConfig.
+type ConfigSnapshot struct {
+	level         Level
+	layerLevels   map[Layer]Level
+	onlyLayers    map[Layer]bool
+	exceptLayers  map[Layer]bool
+	defaultLayer  Layer
+	allowedLayers map[Layer]int
+	sampleEvery   int               // 0 when sampling is off
+	rateLimits    map[Layer]float64 // Lines per second, by layer
+	registry      map[string]*packageConfig
+}
+
+// Level returns the minimum level saved in the snapshot.
+func (s ConfigSnapshot) Level() Level {
+	return s.level
+}
+
+// SnapshotConfig saves the settings that can change at runtime, so they
+// can be changed for a while and put back with RestoreConfig:
+//
+//	saved := logger.SnapshotConfig()
+//	logger.SetLevel(logr.LevelDebug)
+//	defer logger.RestoreConfig(saved)
+//
+// It covers the level and per-layer levels, layer filters, default layer,
+// allowed layers and their priorities, sampling rate, rate limits, and the
+// layers and depths set per package. The snapshot is a deep copy that
+// later changes to the logger don't affect. Outputs, sinks, hooks and the
+// error handler are not part of it, nor is the Config the logger was
+// built with, which doesn't change.
+func (l *Logger) SnapshotConfig() ConfigSnapshot {
+	l.mu.Lock()
+	s := ConfigSnapshot{
+		level:         l.level,
+		layerLevels:   maps.Clone(l.layerLevels),
+		onlyLayers:    maps.Clone(l.onlyLayers),
+		exceptLayers:  maps.Clone(l.exceptLayers),
+		defaultLayer:  l.defaultLayer,
+		allowedLayers: maps.Clone(l.allowedLayers),
+	}
+	if l.sampler != nil {
+		s.sampleEvery = l.sampler.every
+	}
+	l.mu.Unlock()
+
+	l.limiter.mu.Lock()
+	s.rateLimits = make(map[Layer]float64, len(l.limiter.buckets))
+	for layer, bucket := range l.limiter.buckets {
+		s.rateLimits[layer] = bucket.rate
+	}
+	l.limiter.mu.Unlock()
+
+	l.registryMu.RLock()
+	s.registry = copyRegistry(l.registry)
+	l.registryMu.RUnlock()
+
+	return s
+}
+
+// RestoreConfig puts back the settings saved by SnapshotConfig, replacing
+// the current ones. Sampling counters and rate limit budgets carry on for
+// settings that are unchanged and start afresh for the others. Cached
+// layers are dropped, since the per-package layers may differ. The snapshot
+// can be restored more than once.
+func (l *Logger) RestoreConfig(s ConfigSnapshot) {
+	l.mu.Lock()
+	l.level = s.level
+	l.layerLevels = maps.Clone(s.layerLevels)
+	l.onlyLayers = maps.Clone(s.onlyLayers)
+	l.exceptLayers = maps.Clone(s.exceptLayers)
+	l.defaultLayer = s.defaultLayer
+	l.allowedLayers = maps.Clone(s.allowedLayers)
+	if l.allowedLayers == nil {
+		l.allowedLayers = make(map[Layer]int)
+	}
+	switch {
+	case s.sampleEvery == 0:
+		l.sampler = nil
+	case l.sampler == nil || l.sampler.every != s.sampleEvery:
+		l.sampler = &sampler{every: s.sampleEvery, sites: make(map[string]uint64)}
+	}
+	l.mu.Unlock()
+
+	r := l.limiter
+	r.mu.Lock()
+	for layer, bucket := range r.buckets {
+		if rate, ok := s.rateLimits[layer]; !ok || rate != bucket.rate {
+			delete(r.buckets, layer)
+		}
+	}
+	for layer, rate := range s.rateLimits {
+		if _, ok := r.buckets[layer]; ok {
+			continue
+		}
+		r.buckets[layer] = &tokenBucket{rate: rate, tokens: rate, last: nowFunc()}
+		if r.dropped[layer] == nil {
+			r.dropped[layer] = new(atomic.Uint64)
+		}
+	}
+	r.mu.Unlock()
+
+	l.registryMu.Lock()
+	l.registry = copyRegistry(s.registry)
+	l.layerCache.clear()
+	l.registryMu.Unlock()
+}
+
+// copyRegistry returns a deep copy of a per-package registry.
+func copyRegistry(registry map[string]*packageConfig) map[string]*packageConfig {
+	copied := make(map[string]*packageConfig, len(registry))
+	for path, pc := range registry {
+		c := &packageConfig{}
+		if pc.explicitLayer != nil {
+			layer := *pc.explicitLayer
+			c.explicitLayer = &layer
+		}
+		if pc.explicitDepth != nil {
+			depth := *pc.explicitDepth
+			c.explicitDepth = &depth
+		}
+		copied[path] = c
+	}
+	return copied
+}
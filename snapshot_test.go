@@ -0,0 +1,88 @@
+package logr
+
+import (
+	"bytes"
+	"maps"
+	"slices"
+	"testing"
+)
+
+func TestSnapshotRestoreConfig(t *testing.T) {
+	logger := New(&MockFormatter{}, LevelInfo, Config{DefaultDepth: 2})
+	logger.SetLevelForLayer(LayerDB, LevelWarn)
+	logger.ExceptLayers(LayerCORE)
+	logger.SampleEvery(10)
+	logger.SetRateLimit(LayerHTTP, 5)
+	logger.registry["myapp/db"] = &packageConfig{explicitLayer: stringPtr("Database")}
+
+	saved := logger.SnapshotConfig()
+	if saved.Level() != LevelInfo {
+		t.Errorf("snapshot Level() = %s, want INFO", saved.Level())
+	}
+
+	logger.SetLevel(LevelDebug)
+	logger.SetLevelForLayer(LayerDB, LevelDebug)
+	logger.OnlyLayers(LayerHTTP)
+	logger.ClearLayerFilters()
+	logger.SampleEvery(0)
+	logger.SetRateLimit(LayerHTTP, 0)
+	logger.SetRateLimit(LayerDB, 1)
+	*logger.registry["myapp/db"].explicitLayer = "Changed"
+	logger.registry["myapp/api"] = &packageConfig{explicitLayer: stringPtr("API")}
+	if got := resolveLayer(logger, "myapp/db/postgres"); got != "Changed" {
+		t.Fatalf("resolveLayer() = %q before restore, want Changed", got)
+	}
+
+	logger.RestoreConfig(saved)
+
+	if got := logger.GetLevel(); got != LevelInfo {
+		t.Errorf("level = %s, want INFO", got)
+	}
+	if got := logger.thresholdFor(LayerDB); got != LevelWarn {
+		t.Errorf("DB threshold = %s, want WARN", got)
+	}
+	if !logger.filtersOut(LayerCORE) || logger.filtersOut(LayerHTTP) {
+		t.Error("expected the ExceptLayers filter back")
+	}
+	if logger.sampler == nil || logger.sampler.every != 10 {
+		t.Errorf("sampler = %+v, want one in 10", logger.sampler)
+	}
+	if got := slices.Collect(maps.Keys(logger.limiter.buckets)); len(got) != 1 || logger.limiter.buckets[LayerHTTP] == nil {
+		t.Errorf("rate limited layers = %v, want only HTTP", got)
+	}
+	if got := resolveLayer(logger, "myapp/db/postgres"); got != "Database" {
+		t.Errorf("resolveLayer() = %q, want the saved layer", got)
+	}
+	if _, ok := logger.Registry()["myapp/api"]; ok {
+		t.Error("expected a package set after the snapshot to be gone")
+	}
+}
+
+func TestRestoreConfigKeepsUnchangedSampler(t *testing.T) {
+	logger := New(&MockFormatter{}, LevelInfo, Config{DefaultDepth: 2})
+	logger.SetOutput(&bytes.Buffer{})
+	logger.SampleEvery(3)
+
+	saved := logger.SnapshotConfig()
+	for range 3 {
+		logger.Info("sampled") // one call site
+	}
+	logger.RestoreConfig(saved)
+
+	if got := logger.SuppressedCount(); got != 2 {
+		t.Errorf("SuppressedCount() = %d, want the count to carry on", got)
+	}
+}
+
+func TestRestoreConfigTwice(t *testing.T) {
+	logger := New(&MockFormatter{}, LevelInfo, Config{DefaultDepth: 2})
+	saved := logger.SnapshotConfig()
+
+	for range 2 {
+		logger.SetLevelForLayer(LayerDB, LevelError)
+		logger.RestoreConfig(saved)
+		if got := logger.thresholdFor(LayerDB); got != LevelInfo {
+			t.Errorf("DB threshold = %s after restore, want INFO", got)
+		}
+	}
+}
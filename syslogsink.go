@@ -0,0 +1,110 @@
+//go:build !windows && !plan9
+
+package logr
+
+import (
+	"fmt"
+	"log/syslog"
+	"strings"
+	"sync"
+)
+
+// SyslogSink sends log lines to a syslog daemon, local or remote, with a
+// priority derived from each line's level:
+//
+//	LevelError → LOG_ERR
+//	LevelWarn  → LOG_WARNING
+//	LevelInfo  → LOG_INFO, also used for LevelTest
+//	LevelDebug → LOG_DEBUG, also used for LevelTrace
+//
+// If the connection drops and the syslog client's own retry fails, the
+// write returns an error and the next write dials again.
+type SyslogSink struct {
+	network  string
+	raddr    string
+	facility syslog.Priority
+	tag      string
+
+	mu sync.Mutex
+	w  *syslog.Writer // Nil after a failed write until redialed
+}
+
+// NewSyslogSink connects to the syslog daemon at raddr over network
+// ("udp", "tcp", ...). An empty network and raddr use the local daemon.
+// facility is one of the syslog.LOG_* facilities such as syslog.LOG_LOCAL0;
+// tag defaults to the program name when empty.
+func NewSyslogSink(network, raddr string, facility syslog.Priority, tag string) (*SyslogSink, error) {
+	s := &SyslogSink{
+		network:  network,
+		raddr:    raddr,
+		facility: facility,
+		tag:      tag,
+	}
+	if err := s.dial(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Write sends p at LOG_INFO. The logger calls WriteLevel instead.
+func (s *SyslogSink) Write(p []byte) (int, error) {
+	return s.WriteLevel(LevelInfo, p)
+}
+
+// WriteLevel sends p with the priority mapped from level.
+func (s *SyslogSink) WriteLevel(level Level, p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.w == nil {
+		if err := s.dial(); err != nil {
+			return 0, err
+		}
+	}
+
+	msg := strings.TrimSuffix(string(p), "\n")
+	var err error
+	switch {
+	case level == LevelTest:
+		err = s.w.Info(msg)
+	case level >= LevelError:
+		err = s.w.Err(msg)
+	case level == LevelWarn:
+		err = s.w.Warning(msg)
+	case level == LevelInfo:
+		err = s.w.Info(msg)
+	default:
+		err = s.w.Debug(msg)
+	}
+	if err != nil {
+		// Drop the connection so the next write starts from a fresh dial
+		s.w.Close()
+		s.w = nil
+		return 0, fmt.Errorf("logr: write to syslog: %w", err)
+	}
+	return len(p), nil
+}
+
+// Close closes the connection to the syslog daemon.
+func (s *SyslogSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.w == nil {
+		return nil
+	}
+	err := s.w.Close()
+	s.w = nil
+	return err
+}
+
+// dial (re)connects to the daemon. Callers must hold s.mu, except in
+// NewSyslogSink before s is shared.
+func (s *SyslogSink) dial() error {
+	w, err := syslog.Dial(s.network, s.raddr, s.facility, s.tag)
+	if err != nil {
+		return fmt.Errorf("logr: connect to syslog: %w", err)
+	}
+	s.w = w
+	return nil
+}
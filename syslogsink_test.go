@@ -0,0 +1,86 @@
+//go:build !windows && !plan9
+
+package logr
+
+import (
+	"log/syslog"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSyslogSinkPriorities(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("cannot listen on UDP: %v", err)
+	}
+	defer conn.Close()
+
+	sink, err := NewSyslogSink("udp", conn.LocalAddr().String(), syslog.LOG_LOCAL0, "logr-test")
+	if err != nil {
+		t.Fatalf("NewSyslogSink() error = %v", err)
+	}
+	defer sink.Close()
+
+	// LOG_LOCAL0 is 16 << 3 = 128; the priority adds the severity
+	tests := []struct {
+		level  Level
+		prefix string
+	}{
+		{LevelError, "<131>"},
+		{LevelWarn, "<132>"},
+		{LevelInfo, "<134>"},
+		{LevelDebug, "<135>"},
+		{LevelTrace, "<135>"},
+	}
+
+	buf := make([]byte, 1024)
+	for _, tt := range tests {
+		if _, err := sink.WriteLevel(tt.level, []byte("hello "+tt.level.String()+"\n")); err != nil {
+			t.Fatalf("WriteLevel(%s) error = %v", tt.level, err)
+		}
+
+		conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+		n, _, err := conn.ReadFrom(buf)
+		if err != nil {
+			t.Fatalf("reading syslog packet: %v", err)
+		}
+		packet := string(buf[:n])
+		if !strings.HasPrefix(packet, tt.prefix) {
+			t.Errorf("%s: packet %q, want prefix %q", tt.level, packet, tt.prefix)
+		}
+		if !strings.Contains(packet, "logr-test") || !strings.Contains(packet, "hello "+tt.level.String()) {
+			t.Errorf("%s: packet %q missing tag or message", tt.level, packet)
+		}
+	}
+}
+
+func TestSyslogSinkRedialsAfterClose(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("cannot listen on UDP: %v", err)
+	}
+	defer conn.Close()
+
+	sink, err := NewSyslogSink("udp", conn.LocalAddr().String(), syslog.LOG_USER, "logr-test")
+	if err != nil {
+		t.Fatalf("NewSyslogSink() error = %v", err)
+	}
+	sink.Close()
+
+	if _, err := sink.Write([]byte("after close\n")); err != nil {
+		t.Fatalf("Write() after Close error = %v", err)
+	}
+	defer sink.Close()
+
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	buf := make([]byte, 1024)
+	n, _, err := conn.ReadFrom(buf)
+	if err != nil {
+		t.Fatalf("reading syslog packet: %v", err)
+	}
+	if !strings.Contains(string(buf[:n]), "after close") {
+		t.Errorf("packet %q missing message", buf[:n])
+	}
+}
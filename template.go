@@ -0,0 +1,78 @@
+package logr
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TemplateFormatter renders entries from a template of named placeholders,
+// for control over the order and delimiters of plain text lines:
+//
+//	f, err := logr.NewTemplateFormatter("{time} {level} <{layer}> {message} {fields}")
+//	// → 2025-09-30T19:12:02-03:00 INFO <HTTP> request processed userID=456
+//
+// The placeholders are {time}, {level}, {layer}, {message}, {fields} (the
+// error and metadata as key=value pairs) and {caller} (file:line, with
+// Config.IncludeCaller). Everything else is copied as is. Empty values
+// leave any surrounding text in place, and trailing spaces are trimmed.
+// Stack traces follow on indented lines as with PlainTextFormatter.
+type TemplateFormatter struct {
+	parts []templatePart
+}
+
+// templatePart is either literal text or, when field is set, a placeholder.
+type templatePart struct {
+	text  string
+	field func(entry LogEntry) string
+}
+
+var templateFields = map[string]func(entry LogEntry) string{
+	"time":    func(e LogEntry) string { return e.FormattedTime() },
+	"level":   func(e LogEntry) string { return e.Level.String() },
+	"layer":   func(e LogEntry) string { return e.Layer.String() },
+	"message": plainMessage,
+	"fields":  plainFields,
+	"caller":  func(e LogEntry) string { return e.Caller() },
+}
+
+// NewTemplateFormatter parses template and returns a formatter for it. It
+// returns an error for an unknown or unterminated placeholder.
+func NewTemplateFormatter(template string) (*TemplateFormatter, error) {
+	f := &TemplateFormatter{}
+
+	for rest := template; rest != ""; {
+		open := strings.IndexByte(rest, '{')
+		if open == -1 {
+			f.parts = append(f.parts, templatePart{text: rest})
+			break
+		}
+		if open > 0 {
+			f.parts = append(f.parts, templatePart{text: rest[:open]})
+		}
+
+		end := strings.IndexByte(rest[open:], '}')
+		if end == -1 {
+			return nil, fmt.Errorf("logr: template %q: unterminated placeholder at offset %d", template, len(template)-len(rest)+open)
+		}
+		name := rest[open+1 : open+end]
+		field, ok := templateFields[name]
+		if !ok {
+			return nil, fmt.Errorf("logr: template %q: unknown placeholder {%s}", template, name)
+		}
+		f.parts = append(f.parts, templatePart{field: field})
+		rest = rest[open+end+1:]
+	}
+	return f, nil
+}
+
+func (f *TemplateFormatter) Format(entry LogEntry) string {
+	var sb strings.Builder
+	for _, part := range f.parts {
+		if part.field != nil {
+			sb.WriteString(part.field(entry))
+		} else {
+			sb.WriteString(part.text)
+		}
+	}
+	return strings.TrimRight(sb.String(), " ") + plainStack(entry)
+}
@@ -0,0 +1,94 @@
+package logr
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTemplateFormatter(t *testing.T) {
+	f, err := NewTemplateFormatter("{time} {level} <{layer}> {message} {fields}")
+	if err != nil {
+		t.Fatalf("NewTemplateFormatter() error = %v", err)
+	}
+
+	entry := LogEntry{
+		Level:     LevelInfo,
+		Layer:     LayerHTTP,
+		Message:   "request processed",
+		Timestamp: time.Date(2025, 9, 29, 12, 0, 0, 0, time.UTC),
+	}
+	meta := NewMetadata()
+	meta.Add("userID", 456)
+	meta.Add("path", "/users")
+	entry.Metadata = meta
+
+	want := "2025-09-29T12:00:00Z INFO <HTTP> request processed userID=456 path=/users"
+	if got := f.Format(entry); got != want {
+		t.Errorf("Format() = %q, want %q", got, want)
+	}
+
+	// No fields leaves no trailing space
+	entry.Metadata = nil
+	want = "2025-09-29T12:00:00Z INFO <HTTP> request processed"
+	if got := f.Format(entry); got != want {
+		t.Errorf("Format() without fields = %q, want %q", got, want)
+	}
+}
+
+func TestTemplateFormatterCallerAndStack(t *testing.T) {
+	f, err := NewTemplateFormatter("{level}|{caller}|{message}")
+	if err != nil {
+		t.Fatalf("NewTemplateFormatter() error = %v", err)
+	}
+
+	entry := LogEntry{Level: LevelError, Message: "boom", File: "db.go", Line: 7, Stack: "main.main()\n\t/app/main.go:12"}
+	want := "ERROR|db.go:7|boom\n    main.main()\n    \t/app/main.go:12"
+	if got := f.Format(entry); got != want {
+		t.Errorf("Format() = %q, want %q", got, want)
+	}
+}
+
+func TestNewTemplateFormatterErrors(t *testing.T) {
+	tests := []struct {
+		template string
+		want     string
+	}{
+		{"{time} {lvl} {message}", "unknown placeholder {lvl}"},
+		{"{time} {message", "unterminated placeholder at offset 7"},
+		{"{}", "unknown placeholder {}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.template, func(t *testing.T) {
+			f, err := NewTemplateFormatter(tt.template)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("NewTemplateFormatter(%q) error = %v, want %q", tt.template, err, tt.want)
+			}
+			if f != nil {
+				t.Errorf("NewTemplateFormatter(%q) returned a formatter with an error", tt.template)
+			}
+		})
+	}
+}
+
+func TestTemplateFormatterWithLogger(t *testing.T) {
+	f, err := NewTemplateFormatter("{level} {message} {fields}")
+	if err != nil {
+		t.Fatalf("NewTemplateFormatter() error = %v", err)
+	}
+
+	config := DefaultConfig()
+	config.EscapeNewlines = true
+
+	var buf bytes.Buffer
+	logger := New(f, LevelInfo, config)
+	logger.SetOutput(&buf)
+
+	logger.WithField("userID", 5).Info("two\nlines")
+
+	if got, want := buf.String(), "INFO two\\nlines userID=5\n"; got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
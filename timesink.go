@@ -0,0 +1,156 @@
+package logr
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TimeRotatingSink is an io.Writer that starts a new file every Interval
+// and names each file after the period it covers. With Filename
+// "app.log" and the default daily interval, lines go to app-2025-09-29.log
+// until local midnight and then to app-2025-09-30.log. Shorter intervals
+// add the time of day, e.g. app-2025-09-29-150000.log.
+//
+// Files whose period started more than MaxAge ago are deleted when the
+// sink rotates. Close the sink when done. TimeRotatingSink is safe for
+// concurrent use.
+type TimeRotatingSink struct {
+	Filename string
+	Interval time.Duration    // Zero means 24h, starting at local midnight
+	MaxAge   time.Duration    // Zero keeps every file
+	Clock    func() time.Time // Nil means time.Now
+
+	mu     sync.Mutex
+	file   *os.File
+	period time.Time // Start of the period file covers
+}
+
+func (s *TimeRotatingSink) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	period := s.periodStart(s.now())
+	if s.file == nil || !period.Equal(s.period) {
+		if err := s.rotate(period); err != nil {
+			return 0, err
+		}
+	}
+
+	return s.file.Write(p)
+}
+
+// Close closes the current file. A later Write reopens it.
+func (s *TimeRotatingSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.file == nil {
+		return nil
+	}
+	err := s.file.Close()
+	s.file = nil
+	return err
+}
+
+func (s *TimeRotatingSink) now() time.Time {
+	if s.Clock != nil {
+		return s.Clock()
+	}
+	return time.Now()
+}
+
+func (s *TimeRotatingSink) interval() time.Duration {
+	if s.Interval <= 0 {
+		return 24 * time.Hour
+	}
+	return s.Interval
+}
+
+// periodStart returns the start of the period containing t. Whole-day
+// intervals start at midnight in t's location rather than UTC.
+func (s *TimeRotatingSink) periodStart(t time.Time) time.Time {
+	interval := s.interval()
+	if interval%(24*time.Hour) != 0 {
+		return t.Truncate(interval)
+	}
+
+	days := int(interval / (24 * time.Hour))
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	// Count days from a fixed date so multi-day periods line up across restarts
+	epoch := time.Date(1970, 1, 1, 0, 0, 0, 0, t.Location())
+	elapsed := int(midnight.Sub(epoch).Hours()+12) / 24
+	return midnight.AddDate(0, 0, -(elapsed % days))
+}
+
+func (s *TimeRotatingSink) layout() string {
+	if s.interval()%(24*time.Hour) == 0 {
+		return "2006-01-02"
+	}
+	return "2006-01-02-150405"
+}
+
+// nameFor returns the file name for the period starting at period, e.g.
+// "app-2025-09-29.log".
+func (s *TimeRotatingSink) nameFor(period time.Time) string {
+	ext := filepath.Ext(s.Filename)
+	base := strings.TrimSuffix(s.Filename, ext)
+	return base + "-" + period.Format(s.layout()) + ext
+}
+
+// rotate closes the current file, opens the one for period and removes
+// expired files. Callers must hold s.mu.
+func (s *TimeRotatingSink) rotate(period time.Time) error {
+	if s.file != nil {
+		if err := s.file.Close(); err != nil {
+			return fmt.Errorf("logr: rotate log file: %w", err)
+		}
+		s.file = nil
+	}
+
+	f, err := os.OpenFile(s.nameFor(period), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("logr: open log file: %w", err)
+	}
+	s.file = f
+	s.period = period
+
+	s.removeExpired(period)
+	return nil
+}
+
+// removeExpired deletes files from periods that started more than MaxAge
+// before current. It is best effort: a file that can't be removed is
+// retried on the next rotation.
+func (s *TimeRotatingSink) removeExpired(current time.Time) {
+	if s.MaxAge <= 0 {
+		return
+	}
+
+	ext := filepath.Ext(s.Filename)
+	prefix := filepath.Base(strings.TrimSuffix(s.Filename, ext)) + "-"
+	dir := filepath.Dir(s.Filename)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+
+	cutoff := current.Add(-s.MaxAge)
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, ext) {
+			continue
+		}
+		stamp := strings.TrimSuffix(strings.TrimPrefix(name, prefix), ext)
+		period, err := time.ParseInLocation(s.layout(), stamp, current.Location())
+		if err != nil {
+			continue // Not one of ours
+		}
+		if period.Before(cutoff) {
+			os.Remove(filepath.Join(dir, name))
+		}
+	}
+}
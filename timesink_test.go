@@ -0,0 +1,85 @@
+package logr
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// fakeClock returns a Clock whose time is set through the returned pointer.
+func fakeClock(start time.Time) (func() time.Time, *time.Time) {
+	now := start
+	return func() time.Time { return now }, &now
+}
+
+func TestTimeRotatingSinkDaily(t *testing.T) {
+	dir := t.TempDir()
+	clock, now := fakeClock(time.Date(2025, 9, 29, 23, 59, 0, 0, time.UTC))
+	sink := &TimeRotatingSink{Filename: filepath.Join(dir, "app.log"), Clock: clock}
+	defer sink.Close()
+
+	sink.Write([]byte("before midnight\n"))
+	*now = now.Add(2 * time.Minute)
+	sink.Write([]byte("after midnight\n"))
+
+	if got := readFile(t, filepath.Join(dir, "app-2025-09-29.log")); got != "before midnight\n" {
+		t.Errorf("first file = %q", got)
+	}
+	if got := readFile(t, filepath.Join(dir, "app-2025-09-30.log")); got != "after midnight\n" {
+		t.Errorf("second file = %q", got)
+	}
+}
+
+func TestTimeRotatingSinkInterval(t *testing.T) {
+	dir := t.TempDir()
+	clock, now := fakeClock(time.Date(2025, 9, 29, 15, 10, 0, 0, time.UTC))
+	sink := &TimeRotatingSink{Filename: filepath.Join(dir, "app.log"), Interval: time.Hour, Clock: clock}
+	defer sink.Close()
+
+	sink.Write([]byte("one\n"))
+	*now = now.Add(30 * time.Minute)
+	sink.Write([]byte("two\n"))
+	*now = now.Add(30 * time.Minute)
+	sink.Write([]byte("three\n"))
+
+	if got := readFile(t, filepath.Join(dir, "app-2025-09-29-150000.log")); got != "one\ntwo\n" {
+		t.Errorf("15:00 file = %q", got)
+	}
+	if got := readFile(t, filepath.Join(dir, "app-2025-09-29-160000.log")); got != "three\n" {
+		t.Errorf("16:00 file = %q", got)
+	}
+}
+
+func TestTimeRotatingSinkMaxAge(t *testing.T) {
+	dir := t.TempDir()
+	// Unrelated files that share the prefix must survive
+	unrelated := filepath.Join(dir, "app-notes.log")
+	if err := os.WriteFile(unrelated, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	clock, now := fakeClock(time.Date(2025, 9, 1, 12, 0, 0, 0, time.UTC))
+	sink := &TimeRotatingSink{
+		Filename: filepath.Join(dir, "app.log"),
+		MaxAge:   48 * time.Hour,
+		Clock:    clock,
+	}
+	defer sink.Close()
+
+	for day := 0; day < 5; day++ {
+		sink.Write([]byte("line\n"))
+		*now = now.AddDate(0, 0, 1)
+	}
+
+	for _, name := range []string{"app-2025-09-01.log", "app-2025-09-02.log"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); !os.IsNotExist(err) {
+			t.Errorf("expected %s to be removed, stat error = %v", name, err)
+		}
+	}
+	for _, name := range []string{"app-2025-09-03.log", "app-2025-09-04.log", "app-2025-09-05.log", "app-notes.log"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("expected %s to be kept, stat error = %v", name, err)
+		}
+	}
+}